@@ -0,0 +1,60 @@
+package clop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 设置长选项是否可以使用唯一的前缀, 默认是false
+// 打开之后--verb等于--verbose, 完全匹配的选项优先
+func (c *Clop) SetAllowAbbrev(allow bool) *Clop {
+	c.allowAbbrev = allow
+	return c
+}
+
+// 按照名字查找长选项
+// 前缀匹配到多个选项时, option是nil, candidates是所有匹配的选项名
+func (c *Clop) lookupLong(name string) (option *Option, candidates []string) {
+	if o, ok := c.shortAndLong[name]; ok {
+		return o, nil
+	}
+
+	if !c.getRoot().allowAbbrev || name == "" {
+		return nil, nil
+	}
+
+	matched := 0
+	seen := make(map[*Option]struct{})
+	for _, o := range c.shortAndLong {
+		if _, ok := seen[o]; ok {
+			continue
+		}
+		seen[o] = struct{}{}
+
+		for _, long := range o.showLong {
+			if !strings.HasPrefix(long, name) {
+				continue
+			}
+
+			candidates = append(candidates, "--"+long)
+			if option != o {
+				option = o
+				matched++
+			}
+		}
+	}
+
+	if matched <= 1 {
+		return option, nil
+	}
+
+	sort.Strings(candidates)
+	return nil, candidates
+}
+
+func (c *Clop) ambiguousOptionError(name string, candidates []string, index int) error {
+	return &ParseError{Kind: KindAmbiguousOption, Option: "--" + name, Arg: c.args[index],
+		Index: c.argsOffset + index, Source: SourceArgv,
+		msg: fmt.Sprintf("error: The argument '--%s' is ambiguous, possibilities: %s", name, strings.Join(candidates, " "))}
+}
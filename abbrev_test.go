@@ -0,0 +1,57 @@
+package clop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAbbrev struct {
+	Verbose bool     `clop:"-v;--verbose" usage:"verbose"`
+	Version string   `clop:"--version-file" usage:"version file"`
+	Name    string   `clop:"--name" usage:"name"`
+	Names   []string `clop:"--names;greedy" usage:"names"`
+	Color   bool     `clop:"--color;negatable" usage:"color"`
+}
+
+func Test_Abbrev(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		need testAbbrev
+	}{
+		{[]string{"--verb"}, testAbbrev{Verbose: true}},
+		{[]string{"--version-f=a"}, testAbbrev{Version: "a"}},
+		{[]string{"--vers", "a"}, testAbbrev{Version: "a"}},
+		// 完全匹配优先
+		{[]string{"--name", "a"}, testAbbrev{Name: "a"}},
+		{[]string{"--names", "a", "b"}, testAbbrev{Names: []string{"a", "b"}}},
+		// greedy遇到缩写的选项停止
+		{[]string{"--names", "a", "b", "--verb"}, testAbbrev{Verbose: true, Names: []string{"a", "b"}}},
+		{[]string{"--color", "--no-c"}, testAbbrev{}},
+	} {
+		got := testAbbrev{}
+		assert.NoError(t, New(tc.args).SetAllowAbbrev(true).SetExit(false).Bind(&got), tc.args)
+		assert.Equal(t, tc.need, got, tc.args)
+	}
+}
+
+func Test_Abbrev_Ambiguous(t *testing.T) {
+	got := testAbbrev{}
+	err := New([]string{"--ver"}).SetAllowAbbrev(true).SetExit(false).Bind(&got)
+	assert.ErrorIs(t, err, ErrAmbiguousOption)
+
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "--ver", pe.Option)
+	assert.Equal(t, 0, pe.Index)
+	assert.Contains(t, err.Error(), "--verbose --version-file")
+
+	assert.ErrorIs(t, New([]string{"--nam", "a"}).SetAllowAbbrev(true).SetExit(false).Bind(&got), ErrAmbiguousOption)
+}
+
+// 默认不支持缩写
+func Test_Abbrev_Disabled(t *testing.T) {
+	got := testAbbrev{}
+	assert.ErrorIs(t, New([]string{"--verb"}).SetExit(false).Bind(&got), ErrUnknownOption)
+}
@@ -0,0 +1,188 @@
+package clop
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// OptionSpec 用代码注册选项, 用于运行时才知道有哪些选项的场景, 比如插件的manifest
+// 字段和struct tag一一对应
+type OptionSpec struct {
+	Short    string      //短选项, 不带-, 比如"p"
+	Long     string      //长选项, 不带--, 比如"port"
+	Env      string      //环境变量名
+	Usage    string      //帮助信息, 对应usage tag
+	Default  string      //默认值, 对应default tag
+	Valid    string      //数据校验规则, 对应valid tag, 比如"required"
+	Group    string      //选项组, 对应group tag, 比如"source;exclusive"
+	Requires []string    //设置该选项时必须同时设置的选项, 对应requires tag
+	Tag      string      //其它的clop tag选项, 比如"greedy;once"
+	Target   interface{} //保存值的指针, 比如&port
+}
+
+// AddOption 注册选项, 和struct tag注册的选项共用帮助信息, 数据校验和重复检查
+func (c *Clop) AddOption(spec OptionSpec) error {
+	var opts []string
+	if spec.Short != "" {
+		opts = append(opts, "-"+spec.Short)
+	}
+	if spec.Long != "" {
+		opts = append(opts, "--"+spec.Long)
+	}
+	if spec.Env != "" {
+		opts = append(opts, optEnvEqual+spec.Env)
+	}
+	if spec.Tag != "" {
+		opts = append(opts, spec.Tag)
+	}
+
+	return c.addSpec(strings.Join(opts, ";"), spec)
+}
+
+// AddArgs 注册args参数, 等于clop:"args=name"
+func (c *Clop) AddArgs(name, usage string, target interface{}) error {
+	return c.addSpec("args="+name, OptionSpec{Usage: usage, Target: target})
+}
+
+func (c *Clop) addSpec(clop string, spec OptionSpec) error {
+	name := specName(clop, spec)
+	v := reflect.ValueOf(spec.Target)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("%s: %w:got(%T)", name, ErrNotPointerType, spec.Target)
+	}
+
+	if v.IsNil() {
+		return fmt.Errorf("%s: %w", name, ErrUnsupportedType)
+	}
+	v = v.Elem()
+
+	def := strings.TrimSpace(spec.Default)
+	if len(def) > 0 {
+		if err := setDefaultValue(def, "", tagSep(clop), v); err != nil {
+			return &ParseError{Kind: KindInvalidValue, Option: name, Arg: def, Index: -1, Source: SourceDefault, Err: err,
+				msg: fmt.Sprintf("error: Invalid default value '%s' for %s: %v", def, name, err)}
+		}
+	}
+
+	if err := c.parseTagAndSetOption(clop, spec.Usage, def, reflect.StructField{Name: name, Tag: spec.structTag()}, v); err != nil {
+		return err
+	}
+
+	if spec.Valid != "" {
+		c.valids = append(c.valids, validSpec{name: name, valid: spec.Valid, value: v})
+	}
+	return nil
+}
+
+// valid, group, requires不是clop tag的一部分, 转成struct tag, 和结构体的字段一样处理
+func (spec *OptionSpec) structTag() reflect.StructTag {
+	var tags []string
+	for _, t := range []struct{ key, value string }{
+		{"valid", spec.Valid},
+		{"group", spec.Group},
+		{"requires", strings.Join(spec.Requires, ",")},
+	} {
+		if t.value != "" {
+			tags = append(tags, fmt.Sprintf("%s:%q", t.key, t.value))
+		}
+	}
+	return reflect.StructTag(strings.Join(tags, " "))
+}
+
+// 报错信息里面使用的名字, 优先使用长选项
+func specName(clop string, spec OptionSpec) string {
+	switch {
+	case spec.Long != "":
+		return "--" + spec.Long
+	case spec.Short != "":
+		return "-" + spec.Short
+	case spec.Env != "":
+		return spec.Env
+	case strings.HasPrefix(clop, "args="):
+		return "<" + clop[len("args="):] + ">"
+	}
+	return clop
+}
+
+// AddSubcommand 注册子命令, name可以用逗号带上别名, 比如"remove,rm"
+// 子命令已经存在时返回已有的子命令
+func (c *Clop) AddSubcommand(name, usage string) *Clop {
+	names := strings.Split(name, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+
+	if sub, ok := c.subcommand[names[0]]; ok {
+		return sub.Clop
+	}
+	return c.newSubcommand(names, usage)
+}
+
+// Parse 解析命令行, 只用AddOption, AddArgs, AddSubcommand注册选项时使用, 不需要结构体
+func (c *Clop) Parse() (err error) {
+	defer func() {
+		err = c.handleError(err)
+	}()
+
+	if err = c.checkOptionNames(); err != nil {
+		return err
+	}
+	return c.parse()
+}
+
+func (c *Clop) parse() error {
+	if err := c.registerConfigOption(); err != nil {
+		return err
+	}
+
+	if err := c.expandResponseFiles(); err != nil {
+		return err
+	}
+
+	if err := c.bindStruct(); err != nil {
+		return err
+	}
+
+	return c.checkValids()
+}
+
+// AddOption注册的valid规则
+type validSpec struct {
+	name  string
+	valid string
+	value reflect.Value
+}
+
+// 校验root到选中的子命令路径上, AddOption注册的valid规则
+func (c *Clop) checkValids() error {
+	p := c.selected
+	if p == nil {
+		p = c
+	}
+
+	for ; p != nil; p = p.parent {
+		for _, s := range p.valids {
+			valid.lazyinit()
+			err := valid.validate.Var(s.value.Interface(), s.valid)
+			var errs validator.ValidationErrors
+			if errors.As(err, &errs) && len(errs) > 0 {
+				fe := errs[0]
+				kind := KindValidation
+				if fe.Tag() == "required" {
+					kind = KindMissingRequired
+				}
+				return &ParseError{Kind: kind, Option: s.name, Arg: fmt.Sprint(fe.Value()), Index: -1, Err: fe,
+					msg: "error: " + s.name + fe.Translate(valid.trans)}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package clop
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Builder(t *testing.T) {
+	os.Setenv("CLOP_BUILDER_HOST", "example.com")
+	defer os.Unsetenv("CLOP_BUILDER_HOST")
+
+	var (
+		port    int
+		host    string
+		tags    []string
+		files   []string
+		workers int
+		name    string
+	)
+
+	c := New([]string{"--tag", "a", "b", "-p", "8080", "x.txt", "serve", "-w", "4", "web"}).SetExit(false)
+	assert.NoError(t, c.AddOption(OptionSpec{Short: "p", Long: "port", Usage: "port", Default: "80", Target: &port}))
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "host", Env: "CLOP_BUILDER_HOST", Usage: "host", Target: &host}))
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "tag", Tag: "greedy", Usage: "tags", Target: &tags}))
+	assert.NoError(t, c.AddArgs("files", "files", &files))
+
+	serve := c.AddSubcommand("serve,s", "start server")
+	assert.NoError(t, serve.AddOption(OptionSpec{Short: "w", Long: "workers", Usage: "workers", Target: &workers}))
+	assert.NoError(t, serve.AddArgs("name", "name", &name))
+	assert.Equal(t, serve, c.AddSubcommand("serve", "start server"))
+
+	assert.NoError(t, c.Parse())
+	assert.Equal(t, 8080, port)
+	assert.Equal(t, "example.com", host)
+	assert.Equal(t, []string{"a", "b"}, tags)
+	assert.Equal(t, []string{"x.txt", "serve"}, files)
+	assert.Equal(t, 4, workers)
+	assert.Equal(t, "web", name)
+	assert.True(t, c.IsSetSubcommandPath("s"))
+	assert.Equal(t, SourceEnv, c.Lookup("host").Source)
+}
+
+// 和struct tag注册的选项一起使用, 共用重复检查
+func Test_Builder_Struct(t *testing.T) {
+	type app struct {
+		Port int `clop:"-p;--port" usage:"port"`
+	}
+
+	var debug bool
+	got := app{}
+	c := New([]string{"-p", "1", "-d"}).SetExit(false)
+	assert.NoError(t, c.AddOption(OptionSpec{Short: "d", Long: "debug", Usage: "debug", Target: &debug}))
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, 1, got.Port)
+	assert.True(t, debug)
+
+	var port int
+	assert.ErrorIs(t, c.AddOption(OptionSpec{Long: "port", Target: &port}), ErrDuplicateOptions)
+	assert.ErrorIs(t, c.AddOption(OptionSpec{Long: "port2", Target: port}), ErrNotPointerType)
+}
+
+func Test_Builder_Error(t *testing.T) {
+	var port int
+	var name string
+
+	c := New(nil).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "port", Valid: "required", Target: &port}))
+	err := c.Parse()
+	assert.ErrorIs(t, err, ErrMissingRequired)
+	assert.Equal(t, "error: --port must have a value!", err.Error())
+
+	c = New(nil).SetExit(false)
+	assert.ErrorIs(t, c.AddOption(OptionSpec{Long: "port", Default: "abc", Target: &port}), ErrInvalidValue)
+	assert.Error(t, c.AddOption(OptionSpec{Usage: "no name", Target: &name}))
+	assert.ErrorIs(t, c.AddOption(OptionSpec{Long: "name", Tag: "callback", Target: &name}), ErrUnsupported)
+}
+
+func Test_Builder_Help(t *testing.T) {
+	var port int
+	var out bytes.Buffer
+	c := New([]string{"-h"}).SetProcName("app").SetExit(false).SetOutput(&out)
+	assert.NoError(t, c.AddOption(OptionSpec{Short: "p", Long: "port", Usage: "server port", Default: "80", Target: &port}))
+	c.AddSubcommand("serve", "start server")
+	assert.ErrorIs(t, c.Parse(), ErrHelp)
+	assert.Contains(t, out.String(), "-p,--port")
+	assert.Contains(t, out.String(), "server port")
+	assert.Contains(t, out.String(), "start server")
+}
@@ -0,0 +1,131 @@
+package clop
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// 内置支持的标准库类型
+type builtinType struct {
+	name string //帮助信息里面显示的类型名
+	set  func(val string, layout string, value reflect.Value) error
+}
+
+var (
+	typeTime      = reflect.TypeOf(time.Time{})
+	typeTimeSlice = reflect.TypeOf([]time.Time{})
+)
+
+var builtinTypes = map[reflect.Type]builtinType{
+	typeTime:                         {name: "time", set: setTime},
+	reflect.TypeOf(net.IP{}):         {name: "ip", set: setIP},
+	reflect.TypeOf(net.IPNet{}):      {name: "cidr", set: setIPNet},
+	reflect.TypeOf(&net.IPNet{}):     {name: "cidr", set: setIPNet},
+	reflect.TypeOf(url.URL{}):        {name: "url", set: setURL},
+	reflect.TypeOf(&url.URL{}):       {name: "url", set: setURL},
+	reflect.TypeOf(&regexp.Regexp{}): {name: "regexp", set: setRegexp},
+	reflect.TypeOf(os.FileMode(0)):   {name: "mode", set: setFileMode},
+}
+
+// 指针类型直接设置指针, 否则设置指针指向的值
+func assignPtr(value reflect.Value, ptr interface{}) {
+	v := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Ptr {
+		v = v.Elem()
+	}
+	value.Set(v)
+}
+
+// time.Time默认使用RFC3339格式, 可以用layout tag修改
+func setTime(val string, layout string, value reflect.Value) error {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, val)
+	if err != nil {
+		return fmt.Errorf("expected time in layout %s", layout)
+	}
+	value.Set(reflect.ValueOf(t))
+	return nil
+}
+
+func setIP(val string, layout string, value reflect.Value) error {
+	ip := net.ParseIP(val)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address")
+	}
+	value.Set(reflect.ValueOf(ip))
+	return nil
+}
+
+func setIPNet(val string, layout string, value reflect.Value) error {
+	_, ipNet, err := net.ParseCIDR(val)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR address")
+	}
+	assignPtr(value, ipNet)
+	return nil
+}
+
+func setURL(val string, layout string, value reflect.Value) error {
+	u, err := url.Parse(val)
+	if err != nil {
+		return err
+	}
+	assignPtr(value, u)
+	return nil
+}
+
+func setRegexp(val string, layout string, value reflect.Value) error {
+	re, err := regexp.Compile(val)
+	if err != nil {
+		return err
+	}
+	value.Set(reflect.ValueOf(re))
+	return nil
+}
+
+// os.FileMode使用8进制, 比如0644
+func setFileMode(val string, layout string, value reflect.Value) error {
+	mode, err := strconv.ParseUint(val, 8, 32)
+	if err != nil {
+		return fmt.Errorf("expected octal file mode, like 0644")
+	}
+	value.SetUint(mode)
+	return nil
+}
+
+// 使用内置类型设置值, 不是内置类型时ok为false
+func setBuiltin(val string, layout string, value reflect.Value) (ok bool, err error) {
+	b, ok := builtinTypes[value.Type()]
+	if !ok {
+		return false, nil
+	}
+	return true, b.set(val, layout, value)
+}
+
+// 和setBase一样, 多了time.Time和[]time.Time的layout
+func setBaseLayout(val string, layout string, value reflect.Value) error {
+	if layout != "" {
+		switch value.Type() {
+		case typeTime:
+			return setTime(val, layout, value)
+		case typeTimeSlice:
+			t := reflect.New(typeTime).Elem()
+			if err := setTime(val, layout, t); err != nil {
+				return err
+			}
+			value.Set(reflect.Append(value, t))
+			return nil
+		}
+	}
+
+	return setBase(val, value)
+}
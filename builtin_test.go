@@ -0,0 +1,104 @@
+package clop
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBuiltin struct {
+	Since   time.Time      `clop:"--since" usage:"since" layout:"2006-01-02" default:"2020-01-02"`
+	Until   time.Time      `clop:"--until" usage:"until"`
+	Days    []time.Time    `clop:"--day" usage:"days" layout:"2006-01-02"`
+	IP      net.IP         `clop:"--ip" usage:"ip" default:"127.0.0.1"`
+	Subnet  net.IPNet      `clop:"--subnet" usage:"subnet"`
+	Allow   *net.IPNet     `clop:"--allow" usage:"allow"`
+	URL     *url.URL       `clop:"--url" usage:"url" default:"http://localhost:8080/api"`
+	Proxy   url.URL        `clop:"--proxy" usage:"proxy"`
+	Pattern *regexp.Regexp `clop:"--pattern" usage:"pattern"`
+	Mode    os.FileMode    `clop:"--mode" usage:"file mode" default:"0644"`
+}
+
+func Test_Builtin_Default(t *testing.T) {
+	got := testBuiltin{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), got.Since)
+	assert.Equal(t, "127.0.0.1", got.IP.String())
+	assert.Equal(t, "http://localhost:8080/api", got.URL.String())
+	assert.Equal(t, os.FileMode(0644), got.Mode)
+	assert.Nil(t, got.Pattern)
+	assert.Nil(t, got.Allow)
+}
+
+func Test_Builtin(t *testing.T) {
+	got := testBuiltin{}
+	args := []string{
+		"--since", "2021-03-04",
+		"--until", "2021-05-06T07:08:09Z",
+		"--day", "2021-01-01", "--day", "2021-01-02",
+		"--ip", "::1",
+		"--subnet", "10.0.0.0/8",
+		"--allow", "192.168.0.0/16",
+		"--url", "https://example.com",
+		"--proxy", "socks5://127.0.0.1:1080",
+		"--pattern", "^a+$",
+		"--mode", "755",
+	}
+	assert.NoError(t, New(args).SetExit(false).Bind(&got))
+	assert.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), got.Since)
+	assert.Equal(t, time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC), got.Until)
+	assert.Equal(t, []time.Time{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)}, got.Days)
+	assert.Equal(t, net.ParseIP("::1"), got.IP)
+	assert.Equal(t, "10.0.0.0/8", got.Subnet.String())
+	assert.Equal(t, "192.168.0.0/16", got.Allow.String())
+	assert.Equal(t, "https://example.com", got.URL.String())
+	assert.Equal(t, "socks5://127.0.0.1:1080", got.Proxy.String())
+	assert.True(t, got.Pattern.MatchString("aaa"))
+	assert.Equal(t, os.FileMode(0755), got.Mode)
+}
+
+func Test_Builtin_Error(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		msg  string
+	}{
+		{[]string{"--since", "2021/03/04"}, "error: Invalid value '2021/03/04' for '--since': expected time in layout 2006-01-02"},
+		{[]string{"--ip", "1.2.3"}, "error: Invalid value '1.2.3' for '--ip': invalid IP address"},
+		{[]string{"--subnet", "10.0.0.0"}, "error: Invalid value '10.0.0.0' for '--subnet': invalid CIDR address"},
+		{[]string{"--mode", "0999"}, "error: Invalid value '0999' for '--mode': expected octal file mode, like 0644"},
+	} {
+		err := New(tc.args).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&testBuiltin{})
+		assert.ErrorIs(t, err, ErrInvalidValue)
+		assert.EqualError(t, err, tc.msg)
+	}
+}
+
+func Test_Builtin_Help(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testBuiltin{}), ErrHelp)
+	for _, opt := range []string{"--since <time>", "--day <time>", "--ip <ip>", "--subnet <cidr>", "--allow <cidr>", "--url <url>", "--pattern <regexp>", "--mode <mode>"} {
+		assert.Contains(t, out.String(), opt)
+	}
+}
+
+type testBuiltinArgs struct {
+	IP   net.IP   `clop:"args=ip;env=TEST_BUILTIN_IP" usage:"ip"`
+	Rest []string `clop:"args=rest" usage:"rest"`
+}
+
+// net.IP是[]byte, 做为args参数时只接收一个值, 命令行的优先级比环境变量高
+func Test_Builtin_Args(t *testing.T) {
+	os.Setenv("TEST_BUILTIN_IP", "1.1.1.1")
+	defer os.Unsetenv("TEST_BUILTIN_IP")
+
+	got := testBuiltinArgs{}
+	assert.NoError(t, New([]string{"9.9.9.9", "r1", "r2"}).SetExit(false).Bind(&got))
+	assert.Equal(t, net.ParseIP("9.9.9.9"), got.IP)
+	assert.Equal(t, []string{"r1", "r2"}, got.Rest)
+}
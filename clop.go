@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -42,6 +43,13 @@ const (
 	optCallback        = "callback"
 	optCallbackEqual   = "callback="
 	optSpace           = " "
+	optImpliesEqual    = "implies="
+	optConflictsEqual  = "conflicts="
+	optSepEqual        = "sep="
+	optCount           = "count"
+	optMaxEqual        = "max="
+	optNegatable       = "negatable"
+	optRaw             = "raw"
 )
 
 /*
@@ -59,11 +67,13 @@ type Clop struct {
 	//指向自己的root clop，如果设置了subcommand这个值是有意义的
 	//非root Clop指向root，root Clop值为nil
 	root         *Clop
+	parent       *Clop                    //上一层Clop, root为nil
 	shortAndLong map[string]*Option       //存放长短选项
 	checkEnv     map[string]struct{}      //判断环境变量是否重复注册的
 	checkArgs    map[string]struct{}      //判断args是否重复注册
 	envAndArgs   []*Option                //存放环境变量和args
 	args         []string                 //原始参数
+	argsOffset   int                      //args在root命令行参数里面的偏移量, 子命令使用
 	unparsedArgs []unparsedArg            //没有解析的args参数
 	allStruct    map[interface{}]struct{} //所有注册过的结构体
 
@@ -71,17 +81,40 @@ type Clop struct {
 	version       string  //版本信息
 	versionOption *Option //版本选项
 
-	subMain    reflect.Value //子命令带SubMain方法, 就会自动调用
-	structAddr reflect.Value
-	exit       bool                   //测试需要用, 控制-h --help 是否退出进程
-	subcommand map[string]*Subcommand //子命令, 保存结构体当层的所有子命令的信息
+	subMain       reflect.Value //子命令带SubMain()方法, 就会自动调用
+	subMainCtx    reflect.Value //子命令带SubMain(context.Context) error方法, 由Execute调用
+	structAddr    reflect.Value
+	errorHandling ErrorHandling          //出错或者-h --help, -V --version时的行为
+	subcommand    map[string]*Subcommand //子命令, 保存结构体当层的所有子命令的信息
+	groups        []*optionGroup         //选项组, 来自group tag
+	lazy          []lazyPtr              //注册时正在处理的nil指针字段
+	alloc         []lazyPtr              //子命令被选中时才分配的指针字段
+	interspersed  bool                   //选项和参数是否可以混在一起, 只有root才设置该字段
+	rawArgs       []string               //--后面或者停止解析选项之后的参数
+	rawOffset     int                    //rawArgs在c.args里面的位置
+	allowAbbrev   bool                   //长选项是否可以使用唯一的前缀, 只有root才设置该字段
+	responseFiles bool                   //是否展开@file参数
+	argOrigins    []argOrigin            //展开@file之后, 每个参数来自哪个文件的哪一行
+	fieldPath     []string               //注册时正在处理的字段路径, 只有root才设置该字段
+	occurrences   []Occurrence           //命令行里面的每一次设置, 只有root才设置该字段
+	valids        []validSpec            //AddOption注册的valid规则
 
 	isSetSubcommand map[string]struct{} //用于查询哪个子命令被使用, 只有root节点会设置值
 	procName        string              //进程名
 
-	currSubcommandFieldName string //当前使用的子命令结构体名, 只有root才设置该字段
-	fieldName               string //记录当前子结构体字段名, root为空
-	w                       io.Writer
+	fieldName  string    //记录当前子结构体字段名, root为空
+	w          io.Writer //帮助信息, 版本信息的输出
+	errW       io.Writer //错误信息的输出
+	exitCodes  map[ErrorKind]int
+	completion bool  //是否打开隐藏的completion和__complete子命令, 只有root才设置该字段
+	completing bool  //正在处理__complete, 只解析参数, 不调用SubMain, 只有root才设置该字段
+	selected   *Clop //命令行选中的最后一层子命令, 只有root才设置该字段
+
+	configName   string                 //打开内置的--config选项, 也是XDG搜索路径里面的目录名, 只有root才设置该字段
+	configFile   string                 //内置--config选项的值
+	sources      []Source               //配置数据源, 只有root才设置该字段
+	configValues map[string]interface{} //从配置加载的数据, 子命令指向对应的section
+	configLoaded bool
 }
 
 // 设置版本相关信息
@@ -134,28 +167,67 @@ func (c *Clop) SetAbout(about string) *Clop {
 type Subcommand struct {
 	*Clop
 	usage string
+	names []string //子命令名和别名, 第一个是主名字
 }
 
 type Option struct {
 	pointer      reflect.Value //存放需要修改的值的reflect.Value类型变量
 	fn           reflect.Value
-	usage        string //帮助信息
-	showDefValue string //显示默认值
+	complete     reflect.Value //Complete<FieldName>(prefix string) []string, 用于动态补全
+	usage        string        //帮助信息
+	showDefValue string        //显示默认值
 	//表示参数优先级, 高4字节存放args顺序, 低4字节存放命令组合的顺序(ls -ltr)，这里的l的高4字节的值就是0
-	index    uint64
-	envName  string //环境变量
-	argsName string //args变量
-	greedy   bool   //贪婪模式 -H a b c 等于-H a -H b -H c
+	index     uint64
+	envName   string    //环境变量
+	argsName  string    //args变量
+	configKey string    //配置文件里面的key, 来自config tag, 为空使用长选项名
+	layout    string    //time.Time的格式, 来自layout tag
+	sep       string    //sep=, slice类型的值按分隔符切分成多个元素
+	alloc     []lazyPtr //指针字段, 设置值的时候才分配
+	greedy    bool      //贪婪模式 -H a b c 等于-H a -H b -H c
 	// 如果设置once标记，命令行传递-debug -debug这种重复选项会报错
 	// 对slice变量无效
 	once bool //只能设置一次，如果设置once标记，命令行传了两次选项会报错
 
-	cmdSet bool //是否通过命令行设置过值
+	negatable bool    //bool选项自动注册--no-<long>
+	negate    *Option //--no-<long>选项指向的原选项
+
+	raw bool //args=rest;raw, 原样接收--后面的参数
+
+	count    bool //计数选项, -vvv等于3
+	countMax int  //计数选项的最大值, 来自max=, 0表示不限制
+
+	cmdSet   bool        //是否通过命令行设置过值
+	source   ValueSource //值来自哪里
+	rawValue string      //最后一次设置的原始字符串
+	argvPos  int         //最后一次设置在命令行里面的位置, 不是来自命令行的值为-1
+
+	fieldPath string //Go字段的路径, 比如Serve.Workers
+	valid     string //valid tag
+
+	requires  []string       //requires tag, 设置该选项时必须同时设置的选项
+	implies   []impliedValue //implies=, 设置该选项时顺带设置的选项
+	conflicts []string       //conflicts=, 不能和该选项一起设置的选项
 
 	showShort []string //help显示的短选项
 	showLong  []string //help显示的长选项
 }
 
+// 是否是bool, []bool类型或者计数选项, 这类选项不需要跟值
+func (o *Option) isBool() bool {
+	if o.pointer.Kind() == reflect.Bool || o.count {
+		return true
+	}
+
+	_, isBoolSlice := o.pointer.Interface().([]bool)
+	return isBoolSlice
+}
+
+// 是否是可以追加值的slice类型, net.IP这种实现了Value接口或者内置的slice类型只接收一个值
+func (o *Option) isSlice() bool {
+	return o.pointer.Kind() == reflect.Slice && !isValueType(o.pointer.Type())
+}
+
 func (o *Option) onceResetValue() {
 	if len(o.showDefValue) > 0 && !o.pointer.IsZero() && !o.cmdSet {
 		resetValue(o.pointer)
@@ -172,8 +244,10 @@ func New(args []string) *Clop {
 		isSetSubcommand: make(map[string]struct{}), //TODO后期优化下内存,只有root需要初始化
 		allStruct:       make(map[interface{}]struct{}),
 		args:            args,
-		exit:            true,
+		errorHandling:   ExitOnError,
+		interspersed:    true,
 		w:               os.Stdout,
+		errW:            os.Stderr,
 	}
 }
 
@@ -190,13 +264,25 @@ func checkOptionName(name string) (byte, bool) {
 }
 
 // 设置出错行为，默认出错会退出进程(true), 为false则不会
+// SetExit(true)等于SetErrorHandling(ExitOnError), SetExit(false)等于SetErrorHandling(ContinueOnError)
 func (c *Clop) SetExit(exit bool) *Clop {
-	c.exit = exit
-	return c
+	if exit {
+		return c.SetErrorHandling(ExitOnError)
+	}
+	return c.SetErrorHandling(ContinueOnError)
 }
 
+// 设置输出, 帮助信息, 版本信息和错误信息都写到w
+// 错误信息需要单独输出时, 再调用SetErrOutput
 func (c *Clop) SetOutput(w io.Writer) *Clop {
 	c.w = w
+	c.errW = w
+	return c
+}
+
+// 设置错误信息的输出, 默认是os.Stderr
+func (c *Clop) SetErrOutput(w io.Writer) *Clop {
+	c.errW = w
 	return c
 }
 
@@ -273,8 +359,29 @@ func (c *Clop) setOption(name string, option *Option, m map[string]*Option, long
 	return nil
 }
 
-func setValueAndIndex(val string, option *Option, index int, lowIndex int) error {
+func (c *Clop) setValueAndIndex(val string, option *Option, source ValueSource, index int, lowIndex int) error {
+	if source == SourceArgv {
+		c.addOccurrence(option, val, index, lowIndex)
+	}
+
+	if option.negate != nil {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return c.invalidValueError(option, val, source, index, err)
+		}
+
+		// --no-color和--color设置同一个选项, 后出现的优先
+		val = strconv.FormatBool(!b)
+		option = option.negate
+	}
+
 	option.onceResetValue()
+	option.source = source
+	option.rawValue = val
+	option.argvPos = -1
+	if source == SourceArgv {
+		option.argvPos = c.argsOffset + index
+	}
 	option.index = uint64(index) << 31
 	option.index |= uint64(lowIndex)
 	if option.fn.IsValid() {
@@ -283,28 +390,28 @@ func setValueAndIndex(val string, option *Option, index int, lowIndex int) error
 		return nil
 	}
 
-	return setBase(val, option.pointer)
-}
+	allocLazyPtrs(option.alloc)
 
-func errOnce(optionName string) error {
-	return fmt.Errorf(`error: The argument '-%s' was provided more than once, but cannot be used multiple times`,
-		optionName)
-}
-
-func (c *Clop) unknownOptionErrorShort(optionName string, arg string) error {
-	m := fmt.Sprintf(`error: Found argument '-%s' which wasn't expected, or isn't valid in this context`,
-		optionName)
-
-	m += c.genMaybeHelpMsg(arg)
-	return errors.New(m)
-}
+	if option.count {
+		if err := option.setCount(val); err != nil {
+			return c.invalidValueError(option, val, source, index, err)
+		}
+		return nil
+	}
 
-func (c *Clop) unknownOptionError(optionName string) error {
-	m := fmt.Sprintf(`error: Found argument '--%s' which wasn't expected, or isn't valid in this context`,
-		optionName)
+	if parts, ok := splitValue(val, option.sep, source, option.pointer); ok {
+		for _, v := range parts {
+			if err := setBaseLayout(v, option.layout, option.pointer); err != nil {
+				return c.invalidValueError(option, v, source, index, err)
+			}
+		}
+		return nil
+	}
 
-	m += c.genMaybeHelpMsg(optionName)
-	return errors.New(m)
+	if err := setBaseLayout(val, option.layout, option.pointer); err != nil {
+		return c.invalidValueError(option, val, source, index, err)
+	}
+	return nil
 }
 
 func setBoolAndBoolSliceDefval(pointer reflect.Value, value *string) {
@@ -322,23 +429,26 @@ func setBoolAndBoolSliceDefval(pointer reflect.Value, value *string) {
 	}
 }
 
-func (c *Clop) parseEqualValue(arg string) (value string, option *Option, err error) {
-	pos := strings.Index(arg, "=")
-	if pos == -1 {
-		return "", nil, c.unknownOptionError(arg)
+func (c *Clop) parseEqualValue(arg string, index int) (value string, option *Option, err error) {
+	name := arg
+	if pos := strings.Index(arg, "="); pos != -1 {
+		name, value = arg[:pos], arg[pos+1:]
+	}
+
+	option, candidates := c.lookupLong(name)
+	if len(candidates) > 0 {
+		return "", nil, c.ambiguousOptionError(name, candidates, index)
 	}
 
-	option, _ = c.shortAndLong[arg[:pos]]
 	if option == nil {
-		return "", nil, c.unknownOptionError(arg)
+		return "", nil, c.unknownOptionError(arg, index)
 	}
-	value = arg[pos+1:]
 	return value, option, nil
 }
 
-func checkOnce(arg string, option *Option) error {
+func (c *Clop) checkOnce(optionName string, option *Option, index int) error {
 	if option.once && !option.pointer.IsZero() {
-		return errOnce(arg)
+		return c.errOnce(optionName, index)
 	}
 	return nil
 }
@@ -359,8 +469,17 @@ func (c *Clop) isRegisterOptions(arg string) bool {
 		end = e
 	}
 
-	_, ok := c.shortAndLong[arg[num:end]]
-	return ok
+	name := arg[num:end]
+	if _, ok := c.shortAndLong[name]; ok {
+		return true
+	}
+
+	// 长选项的前缀, 有歧义也当作选项
+	if num == 2 {
+		option, candidates := c.lookupLong(name)
+		return option != nil || len(candidates) > 0
+	}
+	return false
 }
 
 // 解析长选项
@@ -369,23 +488,31 @@ func (c *Clop) parseLong(arg string, index *int) (err error) {
 	value := ""
 	option, _ = c.shortAndLong[arg]
 	if option == nil {
-		if value, option, err = c.parseEqualValue(arg); err != nil {
+		if value, option, err = c.parseEqualValue(arg, *index); err != nil {
 			return err
 		}
 	}
 
 	if len(arg) == 1 {
-		return c.unknownOptionError(arg)
+		return c.unknownOptionError(arg, *index)
+	}
+
+	optionName := "--" + arg
+	if pos := strings.IndexByte(arg, '='); pos != -1 {
+		optionName = "--" + arg[:pos]
 	}
 
 	// 设置bool 和bool slice的默认值
 	setBoolAndBoolSliceDefval(option.pointer, &value)
+	if option.count && value == "" {
+		value = countIncrement
+	}
 
 	if len(value) > 0 {
-		if err := checkOnce(arg, option); err != nil {
+		if err := c.checkOnce(optionName, option, *index); err != nil {
 			return err
 		}
-		return setValueAndIndex(value, option, *index, 0)
+		return c.setValueAndIndex(value, option, SourceArgv, *index, 0)
 	}
 
 	// 如果是长选项
@@ -406,11 +533,11 @@ func (c *Clop) parseLong(arg string, index *int) (err error) {
 			return nil
 		}
 
-		if err := checkOnce(arg, option); err != nil {
+		if err := c.checkOnce(optionName, option, *index); err != nil {
 			return err
 		}
 
-		if err := setValueAndIndex(value, option, *index, 0); err != nil {
+		if err := c.setValueAndIndex(value, option, SourceArgv, *index, 0); err != nil {
 			return err
 		}
 
@@ -428,7 +555,8 @@ func (c *Clop) parseLong(arg string, index *int) (err error) {
 
 // 设置环境变量和参数
 func (o *Option) setEnvAndArgs(c *Clop) (err error) {
-	if len(o.envName) > 0 {
+	// 命令行的优先级比环境变量高, slice类型是追加
+	if len(o.envName) > 0 && !(o.cmdSet && !o.isSlice()) {
 		if v, ok := os.LookupEnv(o.envName); ok {
 			if o.pointer.Kind() == reflect.Bool {
 				if v != "false" {
@@ -436,8 +564,18 @@ func (o *Option) setEnvAndArgs(c *Clop) (err error) {
 				}
 			}
 
-			return setValueAndIndex(v, o, 0, 0)
+			return c.setValueAndIndex(v, o, SourceEnv, 0, 0)
+		}
+	}
+
+	// 原样接收停止解析选项之后的参数
+	if o.raw {
+		for i, arg := range c.rawArgs {
+			if err := c.setValueAndIndex(arg, o, SourceArgv, c.rawOffset+i, 0); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
 	if len(o.argsName) > 0 {
@@ -448,10 +586,12 @@ func (o *Option) setEnvAndArgs(c *Clop) (err error) {
 		}
 
 		value := c.unparsedArgs[0]
-		switch o.pointer.Kind() {
-		case reflect.Slice:
-			for o.pointer.Kind() == reflect.Slice {
-				setValueAndIndex(value.arg, o, value.index, 0)
+		switch {
+		case o.isSlice():
+			for {
+				if err := c.setValueAndIndex(value.arg, o, SourceArgv, value.index, 0); err != nil {
+					return err
+				}
 				c.unparsedArgs = c.unparsedArgs[1:]
 				if len(c.unparsedArgs) == 0 {
 					break
@@ -460,7 +600,7 @@ func (o *Option) setEnvAndArgs(c *Clop) (err error) {
 				value = c.unparsedArgs[0]
 			}
 		default:
-			if err := setValueAndIndex(value.arg, o, value.index, 0); err != nil {
+			if err := c.setValueAndIndex(value.arg, o, SourceArgv, value.index, 0); err != nil {
 				return err
 			}
 			if len(c.unparsedArgs) > 0 {
@@ -488,22 +628,21 @@ func (c *Clop) parseShort(arg string, index *int) error {
 	for shortIndex, a = range arg {
 		//只支持ascii
 		if a >= utf8.RuneSelf {
-			return errors.New("Illegal character set")
+			return c.invalidArgumentError("error: Illegal character set", *index)
 		}
 
 		optionName := string(byte(a))
 		option, _ = c.shortAndLong[optionName]
 		if option == nil {
 			//没有注册过的选项直接报错
-			return c.unknownOptionErrorShort(optionName, arg)
+			return c.unknownOptionErrorShort(optionName, arg, *index)
 		}
 
 		find = true
 		findEqual := false //是否找到等于号
 		value := arg
-		_, isBoolSlice := option.pointer.Interface().([]bool)
-		_, isBool := option.pointer.Interface().(bool)
-		if !(isBoolSlice || isBool) {
+		isBool := option.isBool()
+		if !isBool {
 			shortIndex++
 		}
 
@@ -524,7 +663,7 @@ func (c *Clop) parseShort(arg string, index *int) error {
 
 			if len(value[shortIndex:]) > 0 { // 如果没有值，要取args下个参数
 				val := value[shortIndex:]
-				if isBoolSlice || isBool {
+				if isBool {
 					val = "true"
 				}
 
@@ -532,11 +671,11 @@ func (c *Clop) parseShort(arg string, index *int) error {
 					val = string(value[shortIndex:])
 				}
 
-				if err := checkOnce(value[shortIndex:], option); err != nil {
+				if err := c.checkOnce("-"+optionName, option, *index); err != nil {
 					return err
 				}
 
-				if err := setValueAndIndex(val, option, *index, shortIndex); err != nil {
+				if err := c.setValueAndIndex(val, option, SourceArgv, *index, shortIndex); err != nil {
 					return err
 				}
 
@@ -544,7 +683,7 @@ func (c *Clop) parseShort(arg string, index *int) error {
 					return nil
 				}
 
-				if isBoolSlice || isBool { //比如-vvv这种情况
+				if isBool { //比如-vvv这种情况
 					break getchar
 				}
 
@@ -580,11 +719,17 @@ func (c *Clop) parseShort(arg string, index *int) error {
 		return nil
 	}
 
-	return c.unknownOptionErrorShort(arg, arg)
+	return c.unknownOptionErrorShort(arg, arg, *index)
 }
 
 func (c *Clop) findFallbackOpt(value string, index *int) bool {
 
+	// 遇到--也要回退, 留给parseOneOption处理
+	if value == optTerminator {
+		(*index)--
+		return true
+	}
+
 	// 如果打开贪婪模式，直到遇到-或者最后一个字符才结束
 	if strings.HasPrefix(value, "-") {
 		// 如果这个是命令行选项，而不是负数, 就直接回退选项
@@ -598,18 +743,32 @@ func (c *Clop) findFallbackOpt(value string, index *int) bool {
 }
 
 func (c *Clop) getOptionAndSet(arg string, index *int, numMinuses int) error {
+	// 补全的时候不输出帮助和版本信息
+	if c.getRoot().completing && (arg == "h" || arg == "help" || arg == helpJSON || c.version != "" && (arg == c.versionShort() || arg == c.versionLong())) {
+		return nil
+	}
+
+	// 隐藏的--help=json, 输出机器可读的命令行描述
+	if numMinuses == 2 && arg == helpJSON {
+		if _, ok := c.shortAndLong["help"]; !ok {
+			if err := c.printSpec(); err != nil {
+				return err
+			}
+			return ErrHelp
+		}
+	}
+
 	// 输出帮助信息
 	if arg == "h" || arg == "help" {
 		if _, ok := c.shortAndLong[arg]; !ok {
-			c.Usage()
-			return nil
+			c.printHelpMessage()
+			return ErrHelp
 		}
 	}
 
 	// 显示版本信息
 	if c.version != "" && (arg == c.versionShort() || arg == c.versionLong()) {
-		c.showVersion()
-		return nil
+		return c.showVersion()
 	}
 	// 取出option对象
 	switch numMinuses {
@@ -642,8 +801,11 @@ func (c *Clop) showShortAndLong(v *Option) string {
 		oneArgs = append(oneArgs, "-"+v)
 	}
 
-	for _, v := range v.showLong {
-		oneArgs = append(oneArgs, "--"+v)
+	for _, l := range v.showLong {
+		if v.negatable {
+			l = "[no-]" + l
+		}
+		oneArgs = append(oneArgs, "--"+l)
 	}
 	return strings.Join(oneArgs, ",")
 }
@@ -674,17 +836,30 @@ func (c *Clop) genHelpMessage(h *Help) {
 
 			used[v] = struct{}{}
 
+			// --no-<long>和原选项显示在同一行
+			if v.negate != nil {
+				continue
+			}
+
 			// 环境变量
 			env := v.genShowEnvNameValue()
 
 			opt := c.showShortAndLong(v)
+			if typ := valueTypeName(v.pointer); typ != "" {
+				opt += " <" + typ + ">"
+			}
+
+			// 计数选项可以重复
+			if v.count {
+				opt += "..."
+			}
 
 			if h.MaxNameLen < len(opt) {
 				h.MaxNameLen = len(opt)
 			}
 
-			switch v.pointer.Kind() {
-			case reflect.Bool:
+			switch {
+			case v.pointer.Kind() == reflect.Bool || v.count:
 				h.Flags = append(h.Flags, showOption{Opt: opt, Usage: v.usage, Env: env, Default: v.showDefValue})
 			default:
 				h.Options = append(h.Options, showOption{Opt: opt, Usage: v.usage, Env: env, Default: v.showDefValue})
@@ -718,31 +893,36 @@ func (c *Clop) genHelpMessage(h *Help) {
 		h.Args = append(h.Args, showOption{Opt: opt, Usage: v.usage, Env: env})
 	}
 
-	// 子命令
-	for opt, v := range c.subcommand {
+	// 子命令, 别名和主名字显示在同一行
+	for name, v := range c.subcommand {
+		if name != v.names[0] {
+			continue
+		}
+
+		opt := strings.Join(v.names, ", ")
 		if h.MaxNameLen < len(opt) {
 			h.MaxNameLen = len(opt)
 		}
 		h.Subcommand = append(h.Subcommand, showOption{Opt: opt, Usage: v.usage})
 	}
 
-	h.ProcessName = c.procName
+	h.ProcessName = c.fullName()
+	h.Groups = c.genGroupUsage()
 	h.Version = c.version
 	h.About = c.about
 	h.ShowUsageDefault = ShowUsageDefault
 }
 
 // 显示version信息
-func (c *Clop) showVersion() {
+func (c *Clop) showVersion() error {
 	fmt.Fprintln(c.w, c.version)
-	if c.exit {
-		os.Exit(0)
-	}
+	return ErrVersion
 }
 
+// 打印帮助信息, ExitOnError模式会退出进程
 func (c *Clop) Usage() {
 	c.printHelpMessage()
-	if c.exit {
+	if c.errorHandling == ExitOnError {
 		os.Exit(0)
 	}
 }
@@ -777,18 +957,18 @@ func (c *Clop) parseSubcommandTag(clop string, v reflect.Value, usage string, fi
 			name = strings.ToLower(fieldName)
 		}
 		if name != "" {
-			if c.subcommand == nil {
-				c.subcommand = make(map[string]*Subcommand, 3)
+			// subcommand=remove,rm 逗号后面是别名
+			names := strings.Split(name, ",")
+			for i := range names {
+				names[i] = strings.TrimSpace(names[i])
 			}
 
-			newClop := New(nil)
-			//newClop.exit = c.exit //继承exit属性
-			newClop.SetProcName(name)
-			newClop.root = c.getRoot()
-			c.subcommand[name] = &Subcommand{Clop: newClop, usage: usage}
+			newClop := c.newSubcommand(names, usage)
 			newClop.fieldName = fieldName
+			newClop.structAddr = v.Addr()
+			newClop.alloc = append([]lazyPtr(nil), c.lazy...)
 
-			newClop.subMain = v.Addr().MethodByName(defaultSubMain)
+			newClop.subMain, newClop.subMainCtx = subMainMethod(v.Addr())
 			return newClop, true
 		}
 	}
@@ -796,10 +976,42 @@ func (c *Clop) parseSubcommandTag(clop string, v reflect.Value, usage string, fi
 	return nil, false
 }
 
-func (c *Clop) parseTagAndSetOption(clop string, usage string, def string, fieldName string, v reflect.Value) (err error) {
-	options := strings.Split(clop, ";")
+// 创建子命令, names的第一个是主名字, 后面是别名
+func (c *Clop) newSubcommand(names []string, usage string) *Clop {
+	if c.subcommand == nil {
+		c.subcommand = make(map[string]*Subcommand, 3)
+	}
+
+	newClop := New(nil)
+	newClop.SetProcName(names[0])
+	newClop.root = c.getRoot()
+	newClop.parent = c
+	newClop.w = c.w
+	newClop.errW = c.errW
+	sub := &Subcommand{Clop: newClop, usage: usage, names: names}
+	for _, n := range names {
+		c.subcommand[n] = sub
+	}
+	return newClop
+}
 
-	option := &Option{usage: usage, pointer: v, showDefValue: def}
+func (c *Clop) parseTagAndSetOption(clop string, usage string, def string, sf reflect.StructField, v reflect.Value) (err error) {
+	options := strings.Split(clop, ";")
+	fieldName := sf.Name
+
+	option := &Option{usage: usage, pointer: v, showDefValue: def, configKey: Tag(sf.Tag).Get("config"), layout: Tag(sf.Tag).Get("layout")}
+	option.fieldPath = strings.Join(c.getRoot().fieldPath, ".")
+	option.valid = Tag(sf.Tag).Get("valid")
+	option.argvPos = -1
+	if def != "" {
+		option.source = SourceDefault
+		option.rawValue = def
+	}
+	option.complete = c.completeMethod(fieldName)
+	option.alloc = append([]lazyPtr(nil), c.lazy...)
+	if err = c.parseGroupTag(option, sf); err != nil {
+		return err
+	}
 
 	const (
 		isShort = 1 << iota
@@ -822,7 +1034,13 @@ func (c *Clop) parseTagAndSetOption(clop string, usage string, def string, field
 			if strings.HasPrefix(opt, optCallbackEqual) {
 				funcName = opt[len(optCallbackEqual):]
 			}
+			if !c.structAddr.IsValid() {
+				return fmt.Errorf("%s: %w: callback needs a struct method", fieldName, ErrUnsupported)
+			}
 			option.fn = c.structAddr.MethodByName(funcName)
+			if !option.fn.IsValid() {
+				return fmt.Errorf("%s: callback: %w: %s", fieldName, ErrNotFoundName, funcName)
+			}
 			// 检查callback的参数长度
 			if option.fn.Type().NumIn() != 1 {
 				panic(fmt.Sprintf("Required function parameters->%s(val string)", funcName))
@@ -897,6 +1115,33 @@ func (c *Clop) parseTagAndSetOption(clop string, usage string, def string, field
 			c.checkArgs[option.argsName] = struct{}{}
 			c.envAndArgs = append(c.envAndArgs, option)
 
+		case strings.HasPrefix(opt, optImpliesEqual):
+			option.implies = append(option.implies, parseImplies(opt[len(optImpliesEqual):])...)
+		case opt == optRaw:
+			if _, ok := v.Interface().([]string); !ok {
+				return fmt.Errorf("%s: %w: raw only supports []string", fieldName, ErrUnsupportedType)
+			}
+			option.raw = true
+		case opt == optNegatable:
+			if v.Kind() != reflect.Bool {
+				return fmt.Errorf("%s: %w: negatable only supports bool", fieldName, ErrUnsupportedType)
+			}
+			option.negatable = true
+		case opt == optCount:
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				option.count = true
+			default:
+				return fmt.Errorf("%s: %w: count only supports int types", fieldName, ErrUnsupportedType)
+			}
+		case strings.HasPrefix(opt, optMaxEqual):
+			if option.countMax, err = strconv.Atoi(opt[len(optMaxEqual):]); err != nil {
+				return fmt.Errorf("%s: %w:%s", fieldName, ErrUnsupported, opt)
+			}
+		case strings.HasPrefix(opt, optSepEqual):
+			option.sep = opt[len(optSepEqual):]
+		case strings.HasPrefix(opt, optConflictsEqual):
+			option.conflicts = append(option.conflicts, splitOptionNames(opt[len(optConflictsEqual):])...)
 		default:
 			return fmt.Errorf(`%s:(%s) clop:"%s", Maybe you need to clop:"short;long"`, ErrUnsupported, opt, clop)
 		}
@@ -910,33 +1155,59 @@ func (c *Clop) parseTagAndSetOption(clop string, usage string, def string, field
 		return fmt.Errorf("%s:%s", ErrNotFoundName, clop)
 	}
 
-	return nil
+	return c.registerNegatable(option)
 }
 
 func (c *Clop) registerCore(v reflect.Value, sf reflect.StructField) error {
+	// *url.URL这种内置的指针类型, 直接设置指针
 	for v.Kind() == reflect.Ptr {
+		if _, ok := builtinTypes[v.Type()]; ok {
+			break
+		}
+
+		// nil指针先在旁边分配好, 设置值的时候再赋值给字段
+		if v.IsNil() && v.CanSet() {
+			defer c.pushLazyPtr(v)()
+			v = c.lazy[len(c.lazy)-1].ptr.Elem()
+			continue
+		}
 		v = v.Elem()
 	}
 
 	clop := Tag(sf.Tag).Get("clop")
 	usage := Tag(sf.Tag).Get("usage")
 
+	if sf.Name != "" {
+		defer c.pushFieldPath(sf.Name)()
+	}
+
+	// 实现了Value或者encoding.TextUnmarshaler接口的结构体当作普通的选项
+	isStruct := v.Kind() == reflect.Struct && !isValueType(v.Type())
+
 	// 如果是subcommand
-	if v.Kind() == reflect.Struct {
+	if isStruct {
 		if len(clop) != 0 {
 			if newClop, b := c.parseSubcommandTag(clop, v, usage, sf.Name); b {
 				c = newClop
 			}
 		}
+
+		// 普通的嵌套结构体, callback和Complete<Field>方法在字段所在的结构体上查找
+		if v.CanAddr() {
+			defer func(addr reflect.Value) { c.structAddr = addr }(c.structAddr)
+			c.structAddr = v.Addr()
+		}
 	}
 
-	if v.Kind() != reflect.Struct {
+	if !isStruct {
 		def := Tag(sf.Tag).Get("default")
 		def = strings.TrimSpace(def)
 		if len(def) > 0 {
-			if err := setDefaultValue(def, v); err != nil {
-				return err
+			if err := setDefaultValue(def, Tag(sf.Tag).Get("layout"), tagSep(clop), v); err != nil {
+				return &ParseError{Kind: KindInvalidValue, Option: sf.Name, Arg: def, Index: -1, Source: SourceDefault, Err: err,
+					msg: fmt.Sprintf("error: Invalid default value '%s' for field %s: %v", def, sf.Name, err)}
 			}
+			allocLazyPtrs(c.lazy)
 		}
 
 		if len(clop) == 0 && len(usage) == 0 {
@@ -966,11 +1237,10 @@ func (c *Clop) registerCore(v reflect.Value, sf reflect.StructField) error {
 			}
 		}
 
-		return c.parseTagAndSetOption(clop, usage, def, sf.Name, v)
+		return c.parseTagAndSetOption(clop, usage, def, sf, v)
 	}
 
 	typ := v.Type()
-	c.structAddr = v.Addr()
 	for i := 0; i < v.NumField(); i++ {
 		sf := typ.Field(i)
 
@@ -1007,7 +1277,12 @@ func (c *Clop) register(x interface{}) error {
 	}
 
 	c.allStruct[x] = struct{}{}
-	return c.registerCore(v, emptyField)
+	c.structAddr = v
+	if err := c.registerCore(v, emptyField); err != nil {
+		return err
+	}
+
+	return c.checkOptionNames()
 }
 
 func (c *Clop) parseOneOption(index *int) error {
@@ -1015,29 +1290,39 @@ func (c *Clop) parseOneOption(index *int) error {
 	arg := c.args[*index]
 
 	if len(arg) == 0 {
-		return errors.New("fail option")
+		return c.invalidArgumentError("error: Found an empty argument", *index)
 	}
 
 	if arg[0] != '-' {
-		if len(c.subcommand) > 0 {
-			newClop, ok := c.subcommand[arg]
-			// 子命令和args都是没有-号开头，没有设置env或args就当是没有注册过的子命令，直接报错
-			if !ok && len(c.envAndArgs) == 0 {
-				return fmt.Errorf("Unknown subcommand:%s", arg)
-			}
+		newClop, ok := c.subcommand[arg]
+		// 子命令和args都是没有-号开头，没有设置env或args就当是没有注册过的子命令，直接报错
+		if !ok && len(c.subcommand) > 0 && len(c.envAndArgs) == 0 {
+			return c.unknownSubcommandError(arg, *index)
+		}
+
+		// 这个参数本身还是普通参数, 后面的原样保存
+		if !ok && !c.getRoot().interspersed {
+			c.unparsedArgs = append(c.unparsedArgs, unparsedArg{arg: arg, index: *index})
+			c.stopParsing(index, *index+1)
+			return nil
+		}
 
-			c.getRoot().isSetSubcommand[arg] = struct{}{}
-			if c.root == nil {
-				c.currSubcommandFieldName = newClop.fieldName
+		if ok {
+			root := c.getRoot()
+			for _, name := range newClop.names {
+				root.isSetSubcommand[name] = struct{}{}
 			}
+			root.selected = newClop.Clop
+			allocLazyPtrs(newClop.alloc)
 
 			newClop.args = c.args[*index+1:]
+			newClop.argsOffset = c.argsOffset + *index + 1
 			c.args = c.args[0:0]
 			err := newClop.bindStruct()
 			if err != nil {
 				return err
 			}
-			if newClop.subMain.IsValid() {
+			if newClop.subMain.IsValid() && !root.completing {
 				newClop.subMain.Call([]reflect.Value{})
 			}
 		}
@@ -1048,6 +1333,12 @@ func (c *Clop) parseOneOption(index *int) error {
 	// arg 必须是减号开头的字符串
 	numMinuses := 1
 
+	// --后面的都不是选项
+	if arg == optTerminator {
+		c.stopParsing(index, *index+1)
+		return nil
+	}
+
 	if arg == "-" {
 		c.unparsedArgs = append(c.unparsedArgs, unparsedArg{arg: arg, index: *index})
 		return nil
@@ -1063,6 +1354,8 @@ func (c *Clop) parseOneOption(index *int) error {
 
 // 设置环境变量
 func (c *Clop) bindEnvAndArgs() error {
+	c.addRawArgs()
+
 	for _, o := range c.envAndArgs {
 		if err := o.setEnvAndArgs(c); err != nil {
 			return err
@@ -1074,6 +1367,18 @@ func (c *Clop) bindEnvAndArgs() error {
 
 // bind结构体
 func (c *Clop) bindStruct() error {
+	// 隐藏的completion和__complete子命令
+	if c.root == nil && c.completion && len(c.args) > 0 {
+		if _, ok := c.subcommand[c.args[0]]; !ok {
+			switch c.args[0] {
+			case completionSubcommand:
+				return c.completionCommand()
+			case completeSubcommand:
+				return c.completeCommand()
+			}
+		}
+	}
+
 	for i := 0; i < len(c.args); i++ {
 
 		if err := c.parseOneOption(&i); err != nil {
@@ -1082,36 +1387,39 @@ func (c *Clop) bindStruct() error {
 
 	}
 
-	return c.bindEnvAndArgs()
+	if err := c.bindEnvAndArgs(); err != nil {
+		return err
+	}
+
+	if err := c.bindConfig(); err != nil {
+		return err
+	}
+
+	if err := c.applyImplies(); err != nil {
+		return err
+	}
+
+	return c.checkGroups()
 }
 
 func (c *Clop) Bind(x interface{}) (err error) {
 	defer func() {
-		if err != nil {
-			fmt.Fprintln(c.w, err)
-			fmt.Fprintln(c.w, "For more information try --help")
-			if c.exit {
-				os.Exit(1)
-			}
-		}
+		err = c.handleError(err)
 	}()
 
 	if err = c.register(x); err != nil {
 		return err
 	}
 
-	if err = c.bindStruct(); err != nil {
+	if err = c.parse(); err != nil {
 		return err
 	}
 
-	if len(c.currSubcommandFieldName) > 0 {
-		v := reflect.ValueOf(x)
-		v = v.Elem() // x只能是指针，已经在c.register判断过了
-		v = v.FieldByName(c.currSubcommandFieldName)
-		// 只有设置过的子命令才需要数据校验
+	if c.selected != nil && c.selected.structAddr.IsValid() {
+		// 只有最后一层设置过的子命令才需要数据校验
 		// 这里把root结构体删除掉
 		delete(c.allStruct, x)
-		x = v.Addr().Interface()
+		x = c.selected.structAddr.Interface()
 	}
 
 	c.allStruct[x] = struct{}{}
@@ -1123,7 +1431,7 @@ func (c *Clop) Bind(x interface{}) (err error) {
 
 			for _, e := range errs {
 				// can translate each error one at a time.
-				return errors.New(e.Translate(valid.trans))
+				return validationError(e)
 			}
 
 		}
@@ -1132,8 +1440,9 @@ func (c *Clop) Bind(x interface{}) (err error) {
 }
 
 // MustBind 和Bind函数类似， 出错直接panic
+// -h --help, -V --version不算出错
 func (c *Clop) MustBind(x interface{}) {
-	if err := c.Bind(x); err != nil {
+	if err := c.Bind(x); err != nil && !isOutputRequest(err) {
 		panic(err.Error())
 	}
 }
@@ -63,3 +63,38 @@ func Test_Callback_Panic(t *testing.T) {
 	})
 
 }
+
+type TestCallbackInner struct {
+	Name string `clop:"long;callback=SetName" usage:"name"`
+	got  string
+}
+
+func (t *TestCallbackInner) SetName(val string) {
+	t.got = val
+}
+
+type TestCallbackNested struct {
+	Inner TestCallbackInner
+	Max   int `clop:"short;long"`
+}
+
+// 嵌套结构体的callback在字段所在的结构体上查找
+func Test_Callback_Nested(t *testing.T) {
+	got := TestCallbackNested{}
+	p := New([]string{"--name", "x", "--max", "10"}).SetExit(false)
+	err := p.Bind(&got)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "x", got.Inner.got)
+	assert.Equal(t, 10, got.Max)
+}
+
+type TestCallbackNotFound struct {
+	Size int `clop:"short;long;callback=NotFound" usage:"parse size"`
+}
+
+func Test_Callback_NotFound(t *testing.T) {
+	got := TestCallbackNotFound{}
+	p := New([]string{"--size", "1MB"}).SetExit(false)
+	assert.ErrorIs(t, p.Bind(&got), ErrNotFoundName)
+}
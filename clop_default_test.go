@@ -66,7 +66,7 @@ func Test_DefautlValue(t *testing.T) {
 			var out bytes.Buffer
 			p := New([]string{"-h"}).SetExit(false).SetOutput(&out)
 			err := p.Bind(&got)
-			assert.NoError(t, err)
+			assert.ErrorIs(t, err, ErrHelp)
 
 			needTest := []string{
 				`1`,
@@ -76,7 +76,7 @@ func Test_Short(t *testing.T) {
 			var out bytes.Buffer
 			p := New([]string{"-h"}).SetExit(false).SetOutput(&out)
 			err := p.Bind(&got)
-			assert.NoError(t, err)
+			assert.ErrorIs(t, err, ErrHelp)
 
 			needTest := []string{
 				`1`,
@@ -162,7 +162,7 @@ func Test_Long(t *testing.T) {
 			var out bytes.Buffer
 			p := New([]string{"-h"}).SetExit(false).SetOutput(&out)
 			err := p.Bind(&got)
-			assert.NoError(t, err)
+			assert.ErrorIs(t, err, ErrHelp)
 
 			needTest := []string{
 				`1`,
@@ -248,7 +248,7 @@ func Test_LongAndShort(t *testing.T) {
 			var out bytes.Buffer
 			p := New([]string{"-h"}).SetExit(false).SetOutput(&out)
 			err := p.Bind(&got)
-			assert.NoError(t, err)
+			assert.ErrorIs(t, err, ErrHelp)
 
 			needTest := []string{
 				`1`,
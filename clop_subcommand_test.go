@@ -86,7 +86,7 @@ func Test_API_subcommand_SubMain(t *testing.T) {
 				b := &bytes.Buffer{}
 				p.w = b
 				err := p.Bind(&g)
-				assert.NoError(t, err)
+				assert.ErrorIs(t, err, ErrHelp)
 				assert.True(t, checkUsage(b))
 				os.Stdout.Write(b.Bytes())
 				return g
@@ -164,7 +164,7 @@ func Test_API_subcommand(t *testing.T) {
 				b := &bytes.Buffer{}
 				p.w = b
 				err := p.Bind(&g)
-				assert.NoError(t, err)
+				assert.ErrorIs(t, err, ErrHelp)
 				assert.True(t, checkUsage(b))
 				os.Stdout.Write(b.Bytes())
 				return g
@@ -236,8 +236,8 @@ func Test_API_versionAndAbout(t *testing.T) {
 
 			err := p.Bind(&va)
 
-			assert.NoError(t, err)
-			if err != nil {
+			assert.ErrorIs(t, err, ErrHelp)
+			if err != ErrHelp {
 				return err
 			}
 			va.V = p.version
@@ -250,8 +250,8 @@ func Test_API_versionAndAbout(t *testing.T) {
 			va := testVersionAndAbout2{}
 			p := New([]string{"-h"}).SetExit(false)
 			err := p.Bind(&va)
-			assert.NoError(t, err)
-			if err != nil {
+			assert.ErrorIs(t, err, ErrHelp)
+			if err != ErrHelp {
 				return err
 			}
 			assert.Equal(t, p.version, "v0.0.1")
@@ -2,4 +2,11 @@ module github.com/guonaihong/clop/cmd/clop
 
 go 1.16
 
-require github.com/guonaihong/clop v0.1.7
+require (
+	github.com/guonaihong/clop v0.1.7
+	github.com/stretchr/testify v1.7.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// clop parse依赖AddOption, AddSubcommand, Spec, 这些API还没有发布, 使用仓库里面的clop
+replace github.com/guonaihong/clop => ../../
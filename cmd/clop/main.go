@@ -7,14 +7,20 @@ import (
 )
 
 type Cmd struct {
-	FileName   string `clop:"short;long" usage:"go file" valid:"required"`
-	OnlyStruct bool   `clop:"short;long" usage:"only struct" `
+	FileName   string    `clop:"short;long" usage:"go file" valid:"required"`
+	OnlyStruct bool      `clop:"short;long" usage:"only struct" `
+	Parse      *parseCmd `clop:"subcommand=parse" usage:"parse arguments with a cli spec, print shell variables"`
 }
 
 func main() {
 	c := Cmd{}
 	clop.Bind(&c)
 
+	if clop.IsSetSubcommand("parse") {
+		runParse(c.Parse)
+		return
+	}
+
 	p := clop.NewParseFlag().FromFile(c.FileName)
 
 	if c.OnlyStruct {
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 选中的子命令路径输出到这个变量
+const subcommandVar = "CLOP_SUBCOMMAND"
+
+type parseCmd struct {
+	Spec string   `clop:"-s;--spec" usage:"cli spec file, json or yaml" valid:"required"`
+	Args []string `clop:"args=args;raw" usage:"arguments to parse, after --"`
+}
+
+// 按照描述文件解析命令行, 输出shell可以eval的变量赋值
+// 出错或者-h的时候, 帮助信息和错误信息输出到stderr, stdout输出exit N, 让eval的脚本直接退出
+func (p *parseCmd) run(stdout, stderr io.Writer) int {
+	s, err := loadSpec(p.Spec)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	tree, err := s.build(p.Args)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	c := tree.clop.SetExit(false).SetOutput(stderr)
+	if err := c.Parse(); err != nil {
+		code := c.ExitCode(err)
+		fmt.Fprintf(stdout, "exit %d\n", code)
+		return code
+	}
+
+	path := c.SelectedPath()
+	for t, depth := tree, 0; t != nil; depth++ {
+		for _, v := range t.vars {
+			fmt.Fprintf(stdout, "%s=%s\n", v.name, shellValue(v.value))
+		}
+
+		if depth >= len(path) {
+			break
+		}
+		t = t.sub(path[depth])
+	}
+
+	fmt.Fprintf(stdout, "%s=%s\n", subcommandVar, shellQuote(strings.Join(path, " ")))
+	return 0
+}
+
+func (t *specTree) sub(name string) *specTree {
+	c := t.clop.GetSubcommand(name)
+	for _, s := range t.subs {
+		if s.clop == c {
+			return s
+		}
+	}
+	return nil
+}
+
+// slice和map输出成bash数组
+func shellValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = shellQuote(fmt.Sprint(v.Index(i).Interface()))
+		}
+		return "(" + strings.Join(elems, " ") + ")"
+	case reflect.Map:
+		elems := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elems = append(elems, shellQuote(fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface())))
+		}
+		sort.Strings(elems)
+		return "(" + strings.Join(elems, " ") + ")"
+	}
+	return shellQuote(fmt.Sprint(v.Interface()))
+}
+
+// 单引号里面的单引号写成'\”
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func runParse(p *parseCmd) {
+	os.Exit(p.run(os.Stdout, os.Stderr))
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guonaihong/clop"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `{
+	"version": 1,
+	"name": "deploy",
+	"options": [
+		{"short": "p", "long": "port", "env": "DEPLOY_TEST_PORT", "type": "int", "default": "80", "usage": "server port"},
+		{"long": "tag", "type": "[]string", "usage": "tags"}
+	],
+	"subcommands": [
+		{"name": "push", "aliases": ["p"], "usage": "push image",
+		 "options": [{"long": "dry-run", "type": "bool", "usage": "dry run"}],
+		 "args": [{"name": "image", "usage": "image name", "valid": "required", "var": "IMAGE"}]}
+	]
+}`
+
+func testParse(t *testing.T, args ...string) (string, string, int) {
+	return testParseSpec(t, testSpec, args...)
+}
+
+func testParseSpec(t *testing.T, spec string, args ...string) (string, string, int) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spec.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(spec), 0644))
+
+	var stdout, stderr bytes.Buffer
+	code := (&parseCmd{Spec: path, Args: args}).run(&stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func Test_Parse(t *testing.T) {
+	stdout, _, code := testParse(t, "--tag", "a", "--tag", "it's", "p", "--dry-run", "nginx")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "PORT='80'\nTAG=('a' 'it'\\''s')\nPUSH_DRY_RUN='true'\nIMAGE='nginx'\nCLOP_SUBCOMMAND='push'\n", stdout)
+
+	// 没有选中的子命令不输出
+	os.Setenv("DEPLOY_TEST_PORT", "8080")
+	defer os.Unsetenv("DEPLOY_TEST_PORT")
+	stdout, _, code = testParse(t)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "PORT='8080'\nTAG=()\nCLOP_SUBCOMMAND=''\n", stdout)
+}
+
+// 出错和-h的时候输出exit N, 帮助信息和错误信息输出到stderr
+func Test_Parse_Error(t *testing.T) {
+	stdout, stderr, code := testParse(t, "push")
+	assert.Equal(t, 64, code)
+	assert.Equal(t, "exit 64\n", stdout)
+	assert.Contains(t, stderr, "<image> must have a value!")
+
+	stdout, stderr, code = testParse(t, "-h")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "exit 0\n", stdout)
+	assert.Contains(t, stderr, "server port")
+	assert.Contains(t, stderr, "push, p")
+}
+
+// 变量名必须是合法的shell变量名, 不能重复, 自动生成的变量名不能覆盖PATH这类shell变量
+func Test_Parse_VarName(t *testing.T) {
+	for _, tc := range []struct {
+		options string
+		err     string
+	}{
+		{`{"long": "path"}`, "path: variable name PATH is reserved by the shell, set var in the spec"},
+		{`{"long": "bash-env"}`, "bash-env: variable name BASH_ENV is reserved by the shell, set var in the spec"},
+		{`{"long": "2fa"}`, `2fa: invalid variable name "2FA", set var in the spec`},
+		{`{"long": "name", "var": "my-name"}`, `name: invalid variable name "my-name", set var in the spec`},
+		{`{"long": "dry-run"}, {"long": "dry_run"}`, "dry_run: variable name DRY_RUN is already used by dry-run"},
+		{`{"long": "sub", "var": "CLOP_SUBCOMMAND"}`, "sub: variable name CLOP_SUBCOMMAND is already used by CLOP_SUBCOMMAND"},
+	} {
+		stdout, stderr, code := testParseSpec(t, `{"version": 1, "name": "app", "options": [`+tc.options+`]}`)
+		assert.Equal(t, 1, code)
+		assert.Empty(t, stdout)
+		assert.Equal(t, tc.err+"\n", stderr)
+	}
+
+	// 用var指定变量名
+	stdout, _, code := testParseSpec(t, `{"version": 1, "name": "app", "options": [{"long": "path", "var": "SEARCH_PATH"}]}`, "--path", "/tmp")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "SEARCH_PATH='/tmp'\nCLOP_SUBCOMMAND=''\n", stdout)
+}
+
+type testParsePush struct {
+	Force bool   `clop:"-f;--force" usage:"force"`
+	Image string `clop:"args=image" usage:"image name" valid:"required"`
+}
+
+type testParseApp struct {
+	Port    int           `clop:"-p;--port" usage:"port" default:"80"`
+	Verbose int           `clop:"-v;--verbose;count" usage:"verbose"`
+	Color   bool          `clop:"--color;negatable;implies=verbose=2" usage:"color"`
+	Push    testParsePush `clop:"subcommand=push,p" usage:"push image"`
+}
+
+// clop.Spec()输出的json可以直接给clop parse使用
+func Test_Parse_Spec(t *testing.T) {
+	c := clop.New(nil).SetProcName("app")
+	assert.NoError(t, c.Register(&testParseApp{}))
+	data, err := c.Spec()
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spec.json")
+	assert.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+	var stdout, stderr bytes.Buffer
+	code := (&parseCmd{Spec: path, Args: []string{"--color", "p", "-f", "nginx"}}).run(&stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "COLOR='true'\nPORT='80'\nVERBOSE='2'\nPUSH_FORCE='true'\nPUSH_IMAGE='nginx'\nCLOP_SUBCOMMAND='push'\n", stdout.String())
+
+	stdout.Reset()
+	code = (&parseCmd{Spec: path, Args: []string{"--no-color", "-vvv", "push"}}).run(&stdout, &stderr)
+	assert.Equal(t, 64, code)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/guonaihong/clop"
+	"gopkg.in/yaml.v3"
+)
+
+// 命令行描述文件, 支持json和yaml格式, 和clop.Spec的格式兼容
+type spec struct {
+	Version     int          `json:"version" yaml:"version"`
+	Name        string       `json:"name" yaml:"name"`
+	About       string       `json:"about,omitempty" yaml:"about,omitempty"`
+	AppVersion  string       `json:"app_version,omitempty" yaml:"app_version,omitempty"`
+	Options     []specOption `json:"options,omitempty" yaml:"options,omitempty"`
+	Args        []specOption `json:"args,omitempty" yaml:"args,omitempty"`
+	Subcommands []specCmd    `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+}
+
+type specCmd struct {
+	Name        string       `json:"name" yaml:"name"`
+	Aliases     []string     `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Usage       string       `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Options     []specOption `json:"options,omitempty" yaml:"options,omitempty"`
+	Args        []specOption `json:"args,omitempty" yaml:"args,omitempty"`
+	Subcommands []specCmd    `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+}
+
+type specOption struct {
+	clop.SpecOption `yaml:",inline"`
+	Var             string `json:"var,omitempty" yaml:"var,omitempty"` //输出的变量名, 为空使用选项名的大写
+}
+
+// 描述文件里面可以使用的类型
+var specTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		"", false, int(0), int64(0), uint(0), uint64(0), float64(0), time.Duration(0),
+		[]string(nil), []int(nil), []int64(nil), []float64(nil), []bool(nil), map[string]string(nil),
+	} {
+		typ := reflect.TypeOf(v)
+		specTypes[typ.String()] = typ
+	}
+}
+
+func loadSpec(path string) (*spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &spec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, s)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, s)
+	default:
+		return nil, fmt.Errorf("spec file %s: %w", path, clop.ErrUnsupportedConfig)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("spec file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// 解析出来的一个变量
+type specVar struct {
+	name  string
+	value reflect.Value
+}
+
+// 命令和解析结果
+type specTree struct {
+	clop *clop.Clop
+	vars []specVar
+	subs []*specTree
+}
+
+// 按照描述文件注册选项
+func (s *spec) build(args []string) (*specTree, error) {
+	c := clop.New(args).SetProcName(s.Name).SetAbout(s.About)
+	if s.AppVersion != "" {
+		c.SetVersion(s.AppVersion)
+	}
+	return buildCmd(c, "", s.Options, s.Args, s.Subcommands, map[string]string{subcommandVar: subcommandVar})
+}
+
+// vars记录已经使用的变量名, 用于检查重复
+func buildCmd(c *clop.Clop, prefix string, options, args []specOption, subs []specCmd, vars map[string]string) (*specTree, error) {
+	t := &specTree{clop: c}
+	for _, o := range options {
+		if err := t.add(prefix, o, vars, clop.OptionSpec{Short: o.Short, Long: o.Long, Env: o.Env, Tag: o.tag()}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range args {
+		tag := "args=" + o.Name
+		if extra := o.tag(); extra != "" {
+			tag += ";" + extra
+		}
+		if err := t.add(prefix, o, vars, clop.OptionSpec{Env: o.Env, Tag: tag}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sub := range subs {
+		name := strings.Join(append([]string{sub.Name}, sub.Aliases...), ",")
+		subTree, err := buildCmd(c.AddSubcommand(name, sub.Usage), prefix+varName(sub.Name)+"_", sub.Options, sub.Args, sub.Subcommands, vars)
+		if err != nil {
+			return nil, err
+		}
+		t.subs = append(t.subs, subTree)
+	}
+	return t, nil
+}
+
+func (t *specTree) add(prefix string, o specOption, vars map[string]string, opt clop.OptionSpec) error {
+	typ := "string"
+	if o.Type != "" {
+		typ = o.Type
+	}
+
+	rt, ok := specTypes[typ]
+	if !ok {
+		return fmt.Errorf("%s: %w: %s", o.displayName(), clop.ErrUnsupportedType, typ)
+	}
+
+	v := reflect.New(rt)
+	opt.Usage, opt.Default, opt.Valid, opt.Target = o.Usage, o.Default, o.Valid, v.Interface()
+	opt.Group, opt.Requires = o.Group, o.Requires
+	if err := t.clop.AddOption(opt); err != nil {
+		return err
+	}
+
+	name, err := o.varName(prefix, vars)
+	if err != nil {
+		return err
+	}
+	t.vars = append(t.vars, specVar{name: name, value: v.Elem()})
+	return nil
+}
+
+var shellIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// eval之后会覆盖调用者的shell变量, 自动生成的变量名不能使用这些名字, 需要用var指定
+var reservedVars = map[string]bool{
+	"PATH": true, "HOME": true, "IFS": true, "PWD": true, "OLDPWD": true, "CDPATH": true,
+	"SHELL": true, "USER": true, "LOGNAME": true, "HOSTNAME": true, "ENV": true,
+	"LANG": true, "LANGUAGE": true, "TERM": true, "TZ": true, "TMPDIR": true, "MAIL": true, "MAILPATH": true,
+	"PS1": true, "PS2": true, "PS3": true, "PS4": true, "PROMPT_COMMAND": true,
+	"PPID": true, "UID": true, "EUID": true, "GROUPS": true, "RANDOM": true, "SECONDS": true, "LINENO": true,
+	"OPTIND": true, "OPTARG": true, "OPTERR": true, "REPLY": true, "PIPESTATUS": true, "FUNCNAME": true,
+	"SHELLOPTS": true, "GLOBIGNORE": true, "HISTFILE": true, "HISTSIZE": true,
+	"LD_PRELOAD": true, "LD_LIBRARY_PATH": true,
+}
+
+// 输出的变量名, 必须是合法的shell变量名, 而且不能重复
+func (o *specOption) varName(prefix string, vars map[string]string) (string, error) {
+	owner := o.displayName()
+	name := o.Var
+	if name == "" {
+		name = prefix + varName(owner)
+		if reservedVars[name] || strings.HasPrefix(name, "BASH") || strings.HasPrefix(name, "LC_") {
+			return "", fmt.Errorf("%s: variable name %s is reserved by the shell, set var in the spec", owner, name)
+		}
+	}
+
+	if !shellIdent.MatchString(name) {
+		return "", fmt.Errorf("%s: invalid variable name %q, set var in the spec", owner, name)
+	}
+
+	if other, ok := vars[name]; ok {
+		return "", fmt.Errorf("%s: variable name %s is already used by %s", owner, name, other)
+	}
+	vars[name] = owner
+	return name, nil
+}
+
+// 别名, implies和conflicts都是clop tag的一部分
+func (o *specOption) tag() string {
+	tags := append([]string(nil), o.Aliases...)
+	if o.Tag != "" {
+		tags = append(tags, o.Tag)
+	}
+	if len(o.Implies) > 0 {
+		tags = append(tags, "implies="+strings.Join(o.Implies, ","))
+	}
+	if len(o.Conflicts) > 0 {
+		tags = append(tags, "conflicts="+strings.Join(o.Conflicts, ","))
+	}
+	return strings.Join(tags, ";")
+}
+
+func (o *specOption) displayName() string {
+	switch {
+	case o.Long != "":
+		return o.Long
+	case o.Short != "":
+		return o.Short
+	case o.Name != "":
+		return o.Name
+	}
+	return o.Env
+}
+
+// dry-run --> DRY_RUN
+func varName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
@@ -0,0 +1,493 @@
+package clop
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var ErrUnsupportedShell = errors.New("unsupported shell")
+
+// 隐藏的子命令名, 打开SetCompletion之后生效
+const (
+	completionSubcommand  = "completion" //输出补全脚本
+	completeSubcommand    = "__complete" //补全脚本调用, 输出动态补全的候选值
+	defaultCompletePrefix = "Complete"
+)
+
+// 生成补全脚本需要的数据, 一个completionCmd对应一层子命令
+type completionCmd struct {
+	Path      string          //从进程名开始, 空格分隔的子命令路径, 比如"git remote"
+	Subs      []completionSub //当前层的子命令
+	Opts      []string        //当前层所有的选项
+	ValueOpts []string        //需要跟值的选项, 补全文件名
+	DynOpts   []string        //需要跟值的选项, 通过__complete动态补全
+	Args      bool            //是否有args参数
+	DynArgs   bool            //args参数是否通过__complete动态补全
+}
+
+type completionSub struct {
+	Name string
+	Path string
+}
+
+func (c *completionCmd) SubNames() string {
+	names := make([]string, 0, len(c.Subs))
+	for _, s := range c.Subs {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+type completionData struct {
+	Prog string //进程名
+	Func string //shell函数名
+	Cmds []*completionCmd
+}
+
+var completionTmpl = map[string]string{
+	"bash": bashCompletionTmpl,
+	"zsh":  zshCompletionTmpl,
+	"fish": fishCompletionTmpl,
+}
+
+// 设置是否打开隐藏的completion子命令, 比如 proc completion bash
+func (c *Clop) SetCompletion(enable bool) *Clop {
+	c.completion = enable
+	return c
+}
+
+// 生成shell补全脚本, 支持bash, zsh, fish
+// 调用之前需要先Register或者Bind结构体
+func (c *Clop) GenCompletion(shell string, w io.Writer) error {
+	tmpl, ok := completionTmpl[shell]
+	if !ok {
+		return fmt.Errorf("%w: %s (bash, zsh, fish)", ErrUnsupportedShell, shell)
+	}
+
+	prog := c.procName
+	if prog == "" {
+		prog = os.Args[0]
+	}
+	prog = filepath.Base(prog)
+
+	data := completionData{Prog: prog, Func: completionFuncName(prog)}
+	c.genCompletionCmd(prog, &data.Cmds)
+
+	t := template.Must(template.New("clop-completion-" + shell).
+		Funcs(template.FuncMap{"join": strings.Join}).Parse(tmpl))
+	return t.Execute(w, data)
+}
+
+// shell函数名只保留字母数字和下划线
+func completionFuncName(prog string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, prog)
+}
+
+// 递归收集每一层子命令的选项
+func (c *Clop) genCompletionCmd(path string, cmds *[]*completionCmd) {
+	cmd := &completionCmd{Path: path}
+	*cmds = append(*cmds, cmd)
+
+	used := make(map[*Option]struct{}, len(c.shortAndLong))
+	for _, o := range c.shortAndLong {
+		if _, ok := used[o]; ok {
+			continue
+		}
+		used[o] = struct{}{}
+
+		names := make([]string, 0, len(o.showShort)+len(o.showLong))
+		for _, s := range o.showShort {
+			names = append(names, "-"+s)
+		}
+		for _, l := range o.showLong {
+			names = append(names, "--"+l)
+		}
+
+		cmd.Opts = append(cmd.Opts, names...)
+		switch {
+		case o.isBool():
+		case o.complete.IsValid():
+			cmd.DynOpts = append(cmd.DynOpts, names...)
+		default:
+			cmd.ValueOpts = append(cmd.ValueOpts, names...)
+		}
+	}
+
+	if c.shortAndLong["h"] == nil && c.shortAndLong["help"] == nil {
+		cmd.Opts = append(cmd.Opts, "-h", "--help")
+	}
+
+	if c.version != "" {
+		if s := c.versionShort(); s != "" {
+			cmd.Opts = append(cmd.Opts, "-"+s)
+		}
+		if l := c.versionLong(); l != "" {
+			cmd.Opts = append(cmd.Opts, "--"+l)
+		}
+	}
+
+	sort.Strings(cmd.Opts)
+	sort.Strings(cmd.ValueOpts)
+	sort.Strings(cmd.DynOpts)
+
+	for _, o := range c.envAndArgs {
+		if len(o.argsName) > 0 {
+			cmd.Args = true
+			if o.complete.IsValid() {
+				cmd.DynArgs = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(c.subcommand))
+	for name := range c.subcommand {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// 别名和主名字进入同一个cmdpath
+	for _, name := range names {
+		sub := c.subcommand[name]
+		subPath := path + " " + sub.names[0]
+		cmd.Subs = append(cmd.Subs, completionSub{Name: name, Path: subPath})
+		if name == sub.names[0] {
+			sub.genCompletionCmd(subPath, cmds)
+		}
+	}
+}
+
+// 处理隐藏的completion子命令
+func (c *Clop) completionCommand() error {
+	if len(c.args) < 2 {
+		return c.invalidArgumentError("error: The subcommand 'completion' requires a shell name (bash, zsh, fish)", 0)
+	}
+
+	if err := c.GenCompletion(c.args[1], c.w); err != nil {
+		return &ParseError{Kind: KindInvalidArgument, Arg: c.args[1], Index: 1, Source: SourceArgv, Err: err, msg: "error: " + err.Error()}
+	}
+
+	return ErrCompletion
+}
+
+// 查找结构体上的Complete<FieldName>(prefix string) []string方法
+func (c *Clop) completeMethod(fieldName string) reflect.Value {
+	if !c.structAddr.IsValid() {
+		return reflect.Value{}
+	}
+
+	fn := c.structAddr.MethodByName(defaultCompletePrefix + fieldName)
+	if !fn.IsValid() {
+		return fn
+	}
+
+	typ := fn.Type()
+	if typ.NumIn() != 1 || typ.In(0).Kind() != reflect.String ||
+		typ.NumOut() != 1 || typ.Out(0) != reflect.TypeOf([]string(nil)) {
+		return reflect.Value{}
+	}
+	return fn
+}
+
+func callComplete(fn reflect.Value, prefix string) []string {
+	out := fn.Call([]reflect.Value{reflect.ValueOf(prefix)})
+	return out[0].Interface().([]string)
+}
+
+// 处理隐藏的__complete子命令
+// 最后一个参数是正在补全的前缀, 前面的参数会先走一遍正常的解析流程, 这样Complete方法里面可以拿到已经输入的选项
+func (c *Clop) completeCommand() error {
+	words := c.args[1:]
+	prefix := ""
+	if len(words) > 0 {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	c.args = words
+	c.completing = true
+	c.selected = c
+	defer func() { c.completing = false }()
+
+	// 输入到一半的命令行, 出错是正常的, 忽略
+	_ = c.bindStruct()
+
+	prev := ""
+	if len(words) > 0 {
+		prev = words[len(words)-1]
+	}
+
+	for _, s := range c.selected.completeCandidates(prev, prefix) {
+		fmt.Fprintln(c.w, s)
+	}
+
+	return ErrCompletion
+}
+
+// 根据前一个参数和当前前缀生成候选值
+func (c *Clop) completeCandidates(prev, prefix string) (candidates []string) {
+	// --name=value
+	if strings.HasPrefix(prefix, "--") {
+		if pos := strings.IndexByte(prefix, '='); pos != -1 {
+			o := c.shortAndLong[prefix[2:pos]]
+			if o == nil || !o.complete.IsValid() {
+				return nil
+			}
+
+			for _, s := range callComplete(o.complete, prefix[pos+1:]) {
+				candidates = append(candidates, prefix[:pos+1]+s)
+			}
+			return candidates
+		}
+	}
+
+	// --name value
+	if strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") {
+		if o := c.shortAndLong[strings.TrimLeft(prev, "-")]; o != nil && !o.isBool() {
+			if o.complete.IsValid() {
+				return callComplete(o.complete, prefix)
+			}
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(prefix, "-") {
+		cmds := []*completionCmd{}
+		c.genCompletionCmd("", &cmds)
+		for _, opt := range cmds[0].Opts {
+			if strings.HasPrefix(opt, prefix) {
+				candidates = append(candidates, opt)
+			}
+		}
+		return candidates
+	}
+
+	names := make([]string, 0, len(c.subcommand))
+	for name := range c.subcommand {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	candidates = append(candidates, names...)
+
+	// 下一个args参数: 第一个还没有设置的args, 或者slice类型的args
+	for _, o := range c.envAndArgs {
+		if len(o.argsName) == 0 || o.cmdSet && !o.isSlice() {
+			continue
+		}
+
+		if o.complete.IsValid() {
+			candidates = append(candidates, callComplete(o.complete, prefix)...)
+		}
+		break
+	}
+
+	return candidates
+}
+
+var bashCompletionTmpl = `# bash completion for {{.Prog}}, generated by clop
+_{{.Func}}_completion() {
+    local cur prev cmdpath word i
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    cmdpath="{{.Prog}}"
+
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        case "${cmdpath}:${word}" in
+{{- range $cmd := .Cmds}}
+{{- range .Subs}}
+        "{{$cmd.Path}}:{{.Name}}") cmdpath="{{.Path}}" ;;
+{{- end}}
+{{- range .ValueOpts}}
+        "{{$cmd.Path}}:{{.}}") ((i++)) ;;
+{{- end}}
+{{- range .DynOpts}}
+        "{{$cmd.Path}}:{{.}}") ((i++)) ;;
+{{- end}}
+{{- end}}
+        esac
+    done
+
+    case "${cmdpath}" in
+{{- range .Cmds}}
+    "{{.Path}}")
+{{- if or .ValueOpts .DynOpts}}
+        case "${prev}" in
+{{- if .DynOpts}}
+        {{join .DynOpts "|"}})
+            COMPREPLY=($(compgen -W "$("${COMP_WORDS[0]}" __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)" -- "${cur}"))
+            return 0
+            ;;
+{{- end}}
+{{- if .ValueOpts}}
+        {{join .ValueOpts "|"}})
+            COMPREPLY=($(compgen -f -- "${cur}"))
+            return 0
+            ;;
+{{- end}}
+        esac
+{{- end}}
+        if [[ "${cur}" == -* ]]; then
+            COMPREPLY=($(compgen -W "{{join .Opts " "}}" -- "${cur}"))
+        else
+            COMPREPLY=($(compgen -W "{{.SubNames}}" -- "${cur}")
+{{- if .DynArgs}} $(compgen -W "$("${COMP_WORDS[0]}" __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)" -- "${cur}")
+{{- else if .Args}} $(compgen -f -- "${cur}")
+{{- end}})
+        fi
+        ;;
+{{- end}}
+    esac
+    return 0
+}
+
+complete -F _{{.Func}}_completion {{.Prog}}
+`
+
+var zshCompletionTmpl = `#compdef {{.Prog}}
+# zsh completion for {{.Prog}}, generated by clop
+_{{.Func}}_completion() {
+    local cur prev cmdpath word i
+    local -a candidates
+    cur="${words[CURRENT]}"
+    prev="${words[CURRENT-1]}"
+    cmdpath="{{.Prog}}"
+
+    for ((i = 2; i < CURRENT; i++)); do
+        word="${words[i]}"
+        case "${cmdpath}:${word}" in
+{{- range $cmd := .Cmds}}
+{{- range .Subs}}
+        "{{$cmd.Path}}:{{.Name}}") cmdpath="{{.Path}}" ;;
+{{- end}}
+{{- range .ValueOpts}}
+        "{{$cmd.Path}}:{{.}}") ((i++)) ;;
+{{- end}}
+{{- range .DynOpts}}
+        "{{$cmd.Path}}:{{.}}") ((i++)) ;;
+{{- end}}
+{{- end}}
+        esac
+    done
+
+    case "${cmdpath}" in
+{{- range .Cmds}}
+    "{{.Path}}")
+{{- if or .ValueOpts .DynOpts}}
+        case "${prev}" in
+{{- if .DynOpts}}
+        {{join .DynOpts "|"}})
+            candidates=(${(f)"$(${words[1]} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+            compadd -- $candidates
+            return
+            ;;
+{{- end}}
+{{- if .ValueOpts}}
+        {{join .ValueOpts "|"}})
+            _files
+            return
+            ;;
+{{- end}}
+        esac
+{{- end}}
+        if [[ "${cur}" == -* ]]; then
+            compadd -- {{join .Opts " "}}
+        else
+            compadd -- {{.SubNames}}
+{{- if .DynArgs}}
+            candidates=(${(f)"$(${words[1]} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+            compadd -- $candidates
+{{- else if .Args}}
+            _files
+{{- end}}
+        fi
+        ;;
+{{- end}}
+    esac
+}
+
+compdef _{{.Func}}_completion {{.Prog}}
+`
+
+var fishCompletionTmpl = `# fish completion for {{.Prog}}, generated by clop
+function __{{.Func}}_completion
+    set -l words (commandline -opc)
+    set -l cur (commandline -ct)
+    set -l prog $words[1]
+    set -l prev $words[-1]
+    set -l cmdpath '{{.Prog}}'
+    set -l skip 0
+    set -e words[1]
+
+    for word in $words
+        if test $skip -eq 1
+            set skip 0
+            continue
+        end
+        switch "$cmdpath:$word"
+{{- range $cmd := .Cmds}}
+{{- range .Subs}}
+            case '{{$cmd.Path}}:{{.Name}}'
+                set cmdpath '{{.Path}}'
+{{- end}}
+{{- range .ValueOpts}}
+            case '{{$cmd.Path}}:{{.}}'
+                set skip 1
+{{- end}}
+{{- range .DynOpts}}
+            case '{{$cmd.Path}}:{{.}}'
+                set skip 1
+{{- end}}
+{{- end}}
+        end
+    end
+
+    switch $cmdpath
+{{- range .Cmds}}
+        case '{{.Path}}'
+{{- if or .ValueOpts .DynOpts}}
+            switch $prev
+{{- if .DynOpts}}
+                case {{range $i, $o := .DynOpts}}{{if $i}} {{end}}'{{$o}}'{{end}}
+                    $prog __complete $words "$cur" 2>/dev/null
+                    return
+{{- end}}
+{{- if .ValueOpts}}
+                case {{range $i, $o := .ValueOpts}}{{if $i}} {{end}}'{{$o}}'{{end}}
+                    __fish_complete_path $cur
+                    return
+{{- end}}
+            end
+{{- end}}
+            if string match -q -- '-*' $cur
+                printf '%s\n' {{join .Opts " "}}
+            else
+{{- if .Subs}}
+                printf '%s\n' {{.SubNames}}
+{{- end}}
+{{- if .DynArgs}}
+                $prog __complete $words "$cur" 2>/dev/null
+{{- else if .Args}}
+                __fish_complete_path $cur
+{{- end}}
+            end
+{{- end}}
+    end
+end
+
+complete -c {{.Prog}} -f -a '(__{{.Func}}_completion)'
+`
@@ -0,0 +1,128 @@
+package clop
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type completionRemoteAdd struct {
+	Fetch bool   `clop:"-f;--fetch" usage:"fetch the remote branches"`
+	Track string `clop:"-t;--track" usage:"track branch"`
+	Name  string `clop:"args=name" usage:"remote name"`
+}
+
+type completionRemote struct {
+	Verbose bool                `clop:"-v;--verbose" usage:"be verbose"`
+	Add     completionRemoteAdd `clop:"subcommand=add" usage:"add a remote"`
+}
+
+type completionGit struct {
+	GitDir string           `clop:"-C;--git-dir" usage:"set the path to the repository"`
+	Debug  bool             `clop:"-d;--debug" usage:"debug mode"`
+	Remote completionRemote `clop:"subcommand=remote" usage:"manage set of tracked repositories"`
+}
+
+func Test_Completion_Bash(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(nil).SetProcName("/usr/bin/git")
+	assert.NoError(t, c.Register(&completionGit{}))
+	assert.NoError(t, c.GenCompletion("bash", &buf))
+
+	out := buf.String()
+	assert.Contains(t, out, `"git:remote") cmdpath="git remote" ;;`)
+	assert.Contains(t, out, `"git remote:add") cmdpath="git remote add" ;;`)
+	assert.Contains(t, out, `"git:--git-dir") ((i++)) ;;`)
+	assert.Contains(t, out, `compgen -W "--debug --git-dir --help -C -d -h"`)
+	assert.Contains(t, out, `compgen -W "--help --verbose -h -v"`)
+	assert.Contains(t, out, `compgen -W "--fetch --help --track -f -h -t"`)
+	assert.Contains(t, out, "complete -F _git_completion git")
+}
+
+func Test_Completion_Zsh_Fish(t *testing.T) {
+	for _, shell := range []string{"zsh", "fish"} {
+		var buf bytes.Buffer
+		c := New(nil).SetProcName("git")
+		assert.NoError(t, c.Register(&completionGit{}))
+		assert.NoError(t, c.GenCompletion(shell, &buf))
+
+		out := buf.String()
+		assert.Contains(t, out, "git remote add")
+		assert.Contains(t, out, "--fetch --help --track -f -h -t")
+	}
+}
+
+func Test_Completion_UnsupportedShell(t *testing.T) {
+	c := New(nil)
+	assert.NoError(t, c.Register(&completionGit{}))
+	err := c.GenCompletion("powershell", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnsupportedShell)
+}
+
+// 测试隐藏的completion子命令
+func Test_Completion_Subcommand(t *testing.T) {
+	var buf bytes.Buffer
+	g := completionGit{}
+	c := New([]string{"completion", "bash"}).SetProcName("git").SetCompletion(true).SetExit(false).SetOutput(&buf)
+	assert.ErrorIs(t, c.Bind(&g), ErrCompletion)
+	assert.True(t, strings.HasPrefix(buf.String(), "# bash completion for git"))
+
+	// 没有打开SetCompletion, completion当作普通的子命令处理
+	buf.Reset()
+	c = New([]string{"completion", "bash"}).SetProcName("git").SetExit(false).SetOutput(&buf)
+	assert.Error(t, c.Bind(&g))
+	assert.NotContains(t, buf.String(), "# bash completion for git")
+
+	// completion不在help信息里面显示
+	buf.Reset()
+	c = New([]string{"-h"}).SetProcName("git").SetCompletion(true).SetExit(false).SetOutput(&buf)
+	assert.ErrorIs(t, c.Bind(&g), ErrHelp)
+	assert.NotContains(t, buf.String(), "completion")
+}
+
+type completionKubectl struct {
+	Cluster string `clop:"-c;--cluster" usage:"cluster name"`
+	Output  string `clop:"-o;--output" usage:"output format"`
+	Node    string `clop:"args=node" usage:"node name"`
+}
+
+func (k *completionKubectl) CompleteCluster(prefix string) []string {
+	return []string{"prod", "staging"}
+}
+
+// 可以拿到前面已经解析的选项
+func (k *completionKubectl) CompleteNode(prefix string) []string {
+	return []string{k.Cluster + "-node1", k.Cluster + "-node2"}
+}
+
+func testComplete(t *testing.T, args ...string) []string {
+	var buf bytes.Buffer
+	k := completionKubectl{}
+	c := New(append([]string{"__complete"}, args...)).SetProcName("kubectl").SetCompletion(true).SetExit(false).SetOutput(&buf)
+	assert.ErrorIs(t, c.Bind(&k), ErrCompletion)
+	return strings.Fields(buf.String())
+}
+
+// 测试Complete<FieldName>动态补全
+func Test_Completion_Dynamic(t *testing.T) {
+	assert.Equal(t, []string{"prod", "staging"}, testComplete(t, "--cluster", ""))
+	assert.Equal(t, []string{"prod", "staging"}, testComplete(t, "-c", "p"))
+	assert.Equal(t, []string{"--cluster=prod", "--cluster=staging"}, testComplete(t, "--cluster="))
+	assert.Equal(t, []string{"prod-node1", "prod-node2"}, testComplete(t, "-c", "prod", ""))
+	assert.Equal(t, []string{"--cluster"}, testComplete(t, "--c"))
+	assert.Empty(t, testComplete(t, "--output", ""))
+
+	var buf bytes.Buffer
+	c := New(nil).SetProcName("kubectl")
+	assert.NoError(t, c.Register(&completionKubectl{}))
+	assert.NoError(t, c.GenCompletion("bash", &buf))
+	assert.Contains(t, buf.String(), `"${COMP_WORDS[0]}" __complete`)
+
+	// zsh展开没有引号的数组时会丢掉空的元素, 当前的单词为空时也要传给__complete
+	buf.Reset()
+	assert.NoError(t, c.GenCompletion("zsh", &buf))
+	assert.Contains(t, buf.String(), `__complete "${(@)words[2,CURRENT]}"`)
+	assert.NotContains(t, buf.String(), `__complete ${words`)
+}
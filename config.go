@@ -0,0 +1,402 @@
+package clop
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnsupportedConfig = errors.New("unsupported config file format")
+
+// 内置的配置文件选项名
+const optConfig = "config"
+
+// Source 配置数据源
+// 返回的map, key是配置名, value可以是string, bool, 数字, []interface{}, map[string]interface{}
+// map[string]interface{}类型的value对应子命令的配置或者嵌套的配置
+type Source interface {
+	Load() (map[string]interface{}, error)
+}
+
+// ConfigDecoder 把配置文件的内容解析成map
+type ConfigDecoder func(data []byte) (map[string]interface{}, error)
+
+var configDecoders = map[string]ConfigDecoder{
+	".json": decodeJSON,
+	".ini":  decodeINI,
+	".yaml": decodeYAML,
+	".yml":  decodeYAML,
+	".toml": decodeTOML,
+}
+
+// 注册配置文件格式, ext是文件后缀名, 比如".hcl"
+func RegisterConfigDecoder(ext string, d ConfigDecoder) {
+	configDecoders[ext] = d
+}
+
+type fileSource struct {
+	path string
+}
+
+// 根据文件后缀名选择解析格式的配置文件数据源
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (f *fileSource) Load() (map[string]interface{}, error) {
+	decode, ok := configDecoders[strings.ToLower(filepath.Ext(f.path))]
+	if !ok {
+		return nil, fmt.Errorf("error: config file %s: %w", f.path, ErrUnsupportedConfig)
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("error: config file %w", err)
+	}
+
+	m, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("error: config file %s: %w", f.path, err)
+	}
+	return m, nil
+}
+
+// 打开内置的--config选项
+// 命令行没有指定配置文件时, 按照XDG规范搜索
+// $XDG_CONFIG_HOME/<name>/config.<ext>, $XDG_CONFIG_DIRS/<name>/config.<ext>
+func (c *Clop) SetConfig(name string) *Clop {
+	c.configName = name
+	return c
+}
+
+// 添加配置数据源, 后添加的优先级高, 配置文件的优先级比所有Source都高
+func (c *Clop) AddSource(s ...Source) *Clop {
+	c.sources = append(c.sources, s...)
+	return c
+}
+
+// 注册内置的--config选项, 结构体里面已经有--config选项就直接使用
+func (c *Clop) registerConfigOption() error {
+	if c.configName == "" {
+		return nil
+	}
+
+	if _, ok := c.shortAndLong[optConfig]; ok {
+		return nil
+	}
+
+	option := &Option{usage: "config file path", pointer: reflect.ValueOf(&c.configFile).Elem(), argvPos: -1}
+	if err := c.setOption(optConfig, option, c.shortAndLong, true); err != nil {
+		return err
+	}
+	option.showLong = append(option.showLong, optConfig)
+	return nil
+}
+
+// XDG规范的配置文件搜索路径
+func (c *Clop) searchConfigFile() string {
+	var dirs []string
+	home := os.Getenv("XDG_CONFIG_HOME")
+	if home == "" {
+		if h, err := os.UserHomeDir(); err == nil {
+			home = filepath.Join(h, ".config")
+		}
+	}
+	if home != "" {
+		dirs = append(dirs, home)
+	}
+
+	configDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+	dirs = append(dirs, filepath.SplitList(configDirs)...)
+
+	exts := make([]string, 0, len(configDecoders))
+	for ext := range configDecoders {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	for _, dir := range dirs {
+		for _, ext := range exts {
+			path := filepath.Join(dir, c.configName, "config"+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// 加载所有的配置数据, 只在root上调用
+func (c *Clop) loadConfig() (err error) {
+	if c.configLoaded {
+		return nil
+	}
+	c.configLoaded = true
+
+	sources := c.sources
+	if c.configName != "" {
+		path := ""
+		if o, ok := c.shortAndLong[optConfig]; ok && o.pointer.Kind() == reflect.String {
+			path = o.pointer.String()
+			// 来自默认值的配置文件不存在, 不报错
+			if _, err := os.Stat(path); !o.cmdSet && err != nil {
+				path = ""
+			}
+		}
+
+		if path == "" {
+			path = c.searchConfigFile()
+		}
+
+		if path != "" {
+			sources = append(sources, FileSource(path))
+		}
+	}
+
+	for _, s := range sources {
+		m, err := s.Load()
+		if err != nil {
+			return configError(err)
+		}
+
+		if c.configValues == nil {
+			c.configValues = make(map[string]interface{}, len(m))
+		}
+		mergeConfig(c.configValues, m)
+	}
+
+	return nil
+}
+
+// 把src合并到dst, 相同的key, src的优先级高
+func mergeConfig(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if dstSub, ok := dst[k].(map[string]interface{}); ok {
+				mergeConfig(dstSub, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// 当前Clop对应的配置, 子命令使用父命令里面和子命令同名的section
+func (c *Clop) configSection() (map[string]interface{}, error) {
+	if c.parent == nil {
+		if err := c.loadConfig(); err != nil {
+			return nil, err
+		}
+		return c.configValues, nil
+	}
+
+	m, err := c.parent.configSection()
+	if err != nil || m == nil {
+		return nil, err
+	}
+
+	sub, _ := m[c.procName].(map[string]interface{})
+	return sub, nil
+}
+
+// 支持a.b.c这种嵌套的key
+func lookupConfig(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	pos := strings.IndexByte(key, '.')
+	if pos == -1 {
+		return nil, false
+	}
+
+	sub, ok := m[key[:pos]].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupConfig(sub, key[pos+1:])
+}
+
+func (o *Option) getConfigKey() string {
+	if o.configKey != "" {
+		return o.configKey
+	}
+
+	if len(o.showLong) > 0 {
+		return o.showLong[0]
+	}
+	return ""
+}
+
+// 用配置数据设置命令行和环境变量没有设置过的选项
+func (c *Clop) bindConfig() error {
+	m, err := c.configSection()
+	if err != nil || len(m) == 0 {
+		return err
+	}
+
+	used := make(map[*Option]struct{}, len(c.shortAndLong))
+	bind := func(o *Option) error {
+		if _, ok := used[o]; ok {
+			return nil
+		}
+		used[o] = struct{}{}
+
+		if o.cmdSet {
+			return nil
+		}
+
+		key := o.getConfigKey()
+		if key == "" || key == optConfig {
+			return nil
+		}
+
+		v, ok := lookupConfig(m, key)
+		if !ok {
+			return nil
+		}
+
+		return c.setConfigValue(o, key, v)
+	}
+
+	for _, o := range c.shortAndLong {
+		if err := bind(o); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range c.envAndArgs {
+		if err := bind(o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Clop) setConfigValue(o *Option, key string, v interface{}) (err error) {
+	switch val := v.(type) {
+	case []interface{}:
+		if !o.isSlice() {
+			return configError(fmt.Errorf("error: config key '%s' is a list, but the option is not", key))
+		}
+
+		for _, e := range val {
+			if err = c.setValueAndIndex(configString(e), o, SourceConfig, 0, 0); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		var b []byte
+		if b, err = json.Marshal(val); err != nil {
+			return configError(fmt.Errorf("error: config key '%s': %w", key, err))
+		}
+		return c.setValueAndIndex(string(b), o, SourceConfig, 0, 0)
+	default:
+		return c.setValueAndIndex(configString(val), o, SourceConfig, 0, 0)
+	}
+
+	return nil
+}
+
+func configString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+func decodeJSON(data []byte) (m map[string]interface{}, err error) {
+	err = json.Unmarshal(data, &m)
+	return
+}
+
+func decodeYAML(data []byte) (m map[string]interface{}, err error) {
+	err = yaml.Unmarshal(data, &m)
+	return
+}
+
+func decodeTOML(data []byte) (m map[string]interface{}, err error) {
+	err = toml.Unmarshal(data, &m)
+	return
+}
+
+// 解析ini格式
+// [section]或者[section.sub]对应嵌套的map, 重复的key保存为list
+func decodeINI(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	section := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("line %d: invalid section %s", lineNo, line)
+			}
+
+			section = root
+			for _, name := range strings.Split(line[1:len(line)-1], ".") {
+				name = strings.TrimSpace(name)
+				sub, ok := section[name].(map[string]interface{})
+				if !ok {
+					sub = make(map[string]interface{})
+					section[name] = sub
+				}
+				section = sub
+			}
+			continue
+		}
+
+		pos := strings.IndexAny(line, "=:")
+		if pos == -1 {
+			return nil, fmt.Errorf("line %d: missing '=' in %s", lineNo, line)
+		}
+
+		key := strings.TrimSpace(line[:pos])
+		val := strings.TrimSpace(line[pos+1:])
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+
+		switch old := section[key].(type) {
+		case nil:
+			section[key] = val
+		case []interface{}:
+			section[key] = append(old, val)
+		default:
+			section[key] = []interface{}{old, val}
+		}
+	}
+
+	return root, scanner.Err()
+}
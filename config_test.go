@@ -0,0 +1,116 @@
+package clop
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfigServe struct {
+	Workers int `clop:"-w;--workers" usage:"number of workers"`
+}
+
+type testConfig struct {
+	Port  int             `clop:"-p;--port;env=CLOP_CONFIG_PORT" usage:"port" default:"80"`
+	Host  string          `clop:"--host" usage:"host"`
+	Tags  []string        `clop:"--tag" config:"tags" usage:"tags"`
+	Level string          `clop:"--level" config:"log.level" usage:"log level"`
+	Serve testConfigServe `clop:"subcommand=serve" usage:"start server"`
+}
+
+func writeTestConfig(t *testing.T, dir, name, data string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+// 测试优先级 argv > env > config > default
+func Test_Config_Precedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestConfig(t, dir, "app.json", `{"port": 8080, "host": "example.com", "tags": ["a", "b"], "log": {"level": "debug"}}`)
+
+	got := testConfig{}
+	assert.NoError(t, New([]string{"--config", path}).SetConfig("app").SetExit(false).Bind(&got))
+	assert.Equal(t, testConfig{Port: 8080, Host: "example.com", Tags: []string{"a", "b"}, Level: "debug"}, got)
+
+	os.Setenv("CLOP_CONFIG_PORT", "9090")
+	defer os.Unsetenv("CLOP_CONFIG_PORT")
+
+	got = testConfig{}
+	assert.NoError(t, New([]string{"--config", path}).SetConfig("app").SetExit(false).Bind(&got))
+	assert.Equal(t, 9090, got.Port)
+
+	got = testConfig{}
+	assert.NoError(t, New([]string{"--config", path, "-p", "1", "--tag", "c"}).SetConfig("app").SetExit(false).Bind(&got))
+	assert.Equal(t, 1, got.Port)
+	assert.Equal(t, []string{"c"}, got.Tags)
+}
+
+// 测试不同格式的配置文件, 子命令使用同名的section
+func Test_Config_Format(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, file := range []struct {
+		name string
+		data string
+	}{
+		{"app.yaml", "host: example.com\ntags: [a, b]\nserve:\n  workers: 4\n"},
+		{"app.toml", "host = \"example.com\"\ntags = [\"a\", \"b\"]\n[serve]\nworkers = 4\n"},
+		{"app.ini", "host = example.com\ntags = a\ntags = b\n[serve]\nworkers = 4\n"},
+	} {
+		path := writeTestConfig(t, dir, file.name, file.data)
+
+		got := testConfig{}
+		assert.NoError(t, New([]string{"--config", path, "serve"}).SetConfig("app").SetExit(false).Bind(&got), file.name)
+		assert.Equal(t, testConfig{Port: 80, Host: "example.com", Tags: []string{"a", "b"}, Serve: testConfigServe{Workers: 4}}, got, file.name)
+	}
+}
+
+// 测试XDG搜索路径
+func Test_Config_XDG(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestConfig(t, dir, filepath.Join("app", "config.toml"), "host = \"xdg.example.com\"\n")
+
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	got := testConfig{}
+	assert.NoError(t, New(nil).SetConfig("app").SetExit(false).Bind(&got))
+	assert.Equal(t, "xdg.example.com", got.Host)
+}
+
+type testMapSource map[string]interface{}
+
+func (m testMapSource) Load() (map[string]interface{}, error) {
+	return m, nil
+}
+
+// 测试自定义Source
+func Test_Config_Source(t *testing.T) {
+	got := testConfig{}
+	c := New(nil).SetExit(false).AddSource(testMapSource{"host": "a.com", "port": 1}, testMapSource{"host": "b.com"})
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, "b.com", got.Host)
+	assert.Equal(t, 1, got.Port)
+}
+
+func Test_Config_Error(t *testing.T) {
+	got := testConfig{}
+	err := New([]string{"--config", "/not/found/app.json"}).SetConfig("app").SetExit(false).SetOutput(ioutil.Discard).Bind(&got)
+	assert.Error(t, err)
+
+	err = New([]string{"--config", "app.xml"}).SetConfig("app").SetExit(false).SetOutput(ioutil.Discard).Bind(&got)
+	assert.ErrorIs(t, err, ErrUnsupportedConfig)
+}
@@ -0,0 +1,35 @@
+package clop
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// 计数选项每出现一次, 传给setCount的值
+const countIncrement = "true"
+
+// -v, --verbose加1, --verbose=3直接设置
+func (o *Option) setCount(val string) error {
+	n := o.pointer.Int() + 1
+	if val != countIncrement {
+		var err error
+		if n, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return err
+		}
+
+		if o.countMax > 0 && n > int64(o.countMax) {
+			return fmt.Errorf("the maximum is %d", o.countMax)
+		}
+	}
+
+	// 超过最大值不再增加
+	if o.countMax > 0 && n > int64(o.countMax) {
+		n = int64(o.countMax)
+	}
+
+	if o.pointer.OverflowInt(n) {
+		return fmt.Errorf("value out of range")
+	}
+	o.pointer.SetInt(n)
+	return nil
+}
@@ -0,0 +1,51 @@
+package clop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCount struct {
+	Verbose int  `clop:"-v;--verbose;count" usage:"verbose level"`
+	Quiet   int8 `clop:"-q;--quiet;count;max=2" usage:"quiet level"`
+	Debug   bool `clop:"-d" usage:"debug"`
+}
+
+func Test_Count(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		need testCount
+	}{
+		{nil, testCount{}},
+		{[]string{"-v"}, testCount{Verbose: 1}},
+		{[]string{"-vvv"}, testCount{Verbose: 3}},
+		{[]string{"-v", "--verbose", "-vd"}, testCount{Verbose: 3, Debug: true}},
+		{[]string{"--verbose=5"}, testCount{Verbose: 5}},
+		{[]string{"-v=2", "-v"}, testCount{Verbose: 3}},
+		{[]string{"-qqqq"}, testCount{Quiet: 2}},
+	} {
+		got := testCount{}
+		assert.NoError(t, New(tc.args).SetExit(false).Bind(&got), tc.args)
+		assert.Equal(t, tc.need, got, tc.args)
+	}
+}
+
+func Test_Count_Error(t *testing.T) {
+	err := New([]string{"--quiet=3"}).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&testCount{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.EqualError(t, err, "error: Invalid value '3' for '--quiet': the maximum is 2")
+
+	type countString struct {
+		V string `clop:"-v;count" usage:"verbose"`
+	}
+	assert.Error(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&countString{}))
+}
+
+func Test_Count_Help(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testCount{}), ErrHelp)
+	assert.Contains(t, out.String(), "Flags:\n")
+	assert.Contains(t, out.String(), "-v,--verbose...")
+}
@@ -14,11 +14,20 @@ func isDefvalJSON(def []byte) bool {
 	return false
 }
 
-func setDefaultValue(def string, v reflect.Value) error {
+func setDefaultValue(def string, layout string, sep string, v reflect.Value) error {
 	def2 := StringToBytes(def)
-	if isDefvalJSON(def2) {
+	if !isValueType(v.Type()) && isDefvalJSON(def2) {
 		return json.Unmarshal(def2, v.Addr().Interface())
 	}
 
-	return setBase(def, v)
+	if parts, ok := splitValue(def, sep, SourceDefault, v); ok {
+		for _, d := range parts {
+			if err := setBaseLayout(d, layout, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return setBaseLayout(def, layout, v)
 }
@@ -0,0 +1,103 @@
+package clop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrorHandling 定义Bind出错时的行为, 和flag.ErrorHandling一样
+type ErrorHandling int
+
+const (
+	ContinueOnError ErrorHandling = iota // 返回错误
+	ExitOnError                          // 调用os.Exit, 退出码见ExitCode
+	PanicOnError                         // 调用panic
+)
+
+var (
+	// 命令行里面有-h, --help, 帮助信息已经输出
+	ErrHelp = errors.New("clop: help requested")
+	// 命令行里面有-V, --version, 版本信息已经输出
+	ErrVersion = errors.New("clop: version requested")
+	// 隐藏的completion, __complete子命令, 补全信息已经输出
+	ErrCompletion = errors.New("clop: completion requested")
+)
+
+// 参考sysexits.h
+const (
+	ExitUsage  = 64 // EX_USAGE, 命令行用法错误
+	ExitConfig = 78 // EX_CONFIG, 配置错误
+)
+
+// 设置出错行为
+func (c *Clop) SetErrorHandling(h ErrorHandling) *Clop {
+	c.errorHandling = h
+	return c
+}
+
+// 设置某种错误的退出码
+func (c *Clop) SetExitCode(kind ErrorKind, code int) *Clop {
+	if c.exitCodes == nil {
+		c.exitCodes = make(map[ErrorKind]int)
+	}
+	c.exitCodes[kind] = code
+	return c
+}
+
+// 错误对应的进程退出码
+// ErrHelp, ErrVersion是0, ParseError默认是64(EX_USAGE), 配置错误是78(EX_CONFIG)
+// 实现了ExitCode() int方法的错误使用自己的退出码, 其它错误是1
+func (c *Clop) ExitCode(err error) int {
+	if err == nil || isOutputRequest(err) {
+		return 0
+	}
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		if code, ok := c.exitCodes[pe.Kind]; ok {
+			return code
+		}
+
+		if pe.Kind == KindConfig {
+			return ExitConfig
+		}
+		return ExitUsage
+	}
+
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	return 1
+}
+
+// 帮助信息, 版本信息, 补全信息已经输出, 不是真正的错误
+func isOutputRequest(err error) bool {
+	return errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) || errors.Is(err, ErrCompletion)
+}
+
+// 根据ErrorHandling处理Bind返回的错误
+func (c *Clop) handleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if !isOutputRequest(err) {
+		fmt.Fprintln(c.errW, err)
+		fmt.Fprintln(c.errW, "For more information try --help")
+	}
+
+	return c.applyErrorHandling(err)
+}
+
+// 按照ErrorHandling退出进程, panic或者返回错误
+func (c *Clop) applyErrorHandling(err error) error {
+	switch c.errorHandling {
+	case ExitOnError:
+		os.Exit(c.ExitCode(err))
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
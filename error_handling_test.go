@@ -0,0 +1,67 @@
+package clop
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testErrorHandling struct {
+	Port int `clop:"-p;--port" usage:"port"`
+}
+
+func Test_ErrorHandling_Continue(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	got := testErrorHandling{}
+	c := New([]string{"-h"}).SetErrorHandling(ContinueOnError).SetOutput(&out).SetErrOutput(&errOut)
+	err := c.Bind(&got)
+	assert.ErrorIs(t, err, ErrHelp)
+	assert.Contains(t, out.String(), "--port")
+	assert.Empty(t, errOut.String())
+	assert.Equal(t, 0, c.ExitCode(err))
+
+	out.Reset()
+	c = New([]string{"-V"}).SetVersion("v0.0.1").SetErrorHandling(ContinueOnError).SetOutput(&out).SetErrOutput(&errOut)
+	err = c.Bind(&got)
+	assert.ErrorIs(t, err, ErrVersion)
+	assert.Equal(t, "v0.0.1\n", out.String())
+
+	// 错误信息只写到错误输出
+	out.Reset()
+	c = New([]string{"--port", "abc"}).SetErrorHandling(ContinueOnError).SetOutput(&out).SetErrOutput(&errOut)
+	err = c.Bind(&got)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Empty(t, out.String())
+	assert.Contains(t, errOut.String(), "Invalid value 'abc' for '--port'")
+	assert.Equal(t, ExitUsage, c.ExitCode(err))
+}
+
+func Test_ErrorHandling_Panic(t *testing.T) {
+	got := testErrorHandling{}
+	c := New([]string{"--unknown"}).SetErrorHandling(PanicOnError).SetErrOutput(&bytes.Buffer{})
+	assert.Panics(t, func() { c.Bind(&got) })
+
+	// -h不是错误, MustBind不会panic
+	c = New([]string{"-h"}).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.NotPanics(t, func() { c.MustBind(&got) })
+}
+
+type testExitCodeError struct{}
+
+func (testExitCodeError) Error() string { return "exit code error" }
+func (testExitCodeError) ExitCode() int { return 3 }
+
+func Test_ErrorHandling_ExitCode(t *testing.T) {
+	c := New(nil).SetExitCode(KindUnknownOption, 2)
+
+	assert.Equal(t, 0, c.ExitCode(nil))
+	assert.Equal(t, 0, c.ExitCode(ErrHelp))
+	assert.Equal(t, 2, c.ExitCode(&ParseError{Kind: KindUnknownOption}))
+	assert.Equal(t, ExitUsage, c.ExitCode(&ParseError{Kind: KindInvalidValue}))
+	assert.Equal(t, ExitConfig, c.ExitCode(&ParseError{Kind: KindConfig}))
+	assert.Equal(t, 3, c.ExitCode(testExitCodeError{}))
+	assert.Equal(t, 1, c.ExitCode(errors.New("other")))
+}
@@ -0,0 +1,197 @@
+package clop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorKind 解析错误的类型
+type ErrorKind int
+
+const (
+	KindUnknownOption     ErrorKind = iota + 1 //没有注册过的选项
+	KindUnknownSubcommand                      //没有注册过的子命令
+	KindInvalidValue                           //选项的值转换失败
+	KindRepeatedOption                         //once选项出现了多次
+	KindMissingRequired                        //required选项没有值
+	KindValidation                             //valid tag校验失败
+	KindInvalidArgument                        //命令行参数本身不合法, 比如空字符串
+	KindConfig                                 //配置文件读取或者解析失败
+	KindConflict                               //互斥的选项同时出现
+	KindAmbiguousOption                        //长选项的前缀匹配到多个选项
+)
+
+// 和ErrorKind一一对应, 用于errors.Is
+var (
+	ErrUnknownOption     = errors.New("unknown option")
+	ErrUnknownSubcommand = errors.New("unknown subcommand")
+	ErrInvalidValue      = errors.New("invalid value")
+	ErrRepeatedOption    = errors.New("option provided more than once")
+	ErrMissingRequired   = errors.New("missing required value")
+	ErrValidation        = errors.New("validation failed")
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrConfig            = errors.New("invalid config")
+	ErrConflict          = errors.New("conflicting options")
+	ErrAmbiguousOption   = errors.New("ambiguous option")
+)
+
+var kindErrors = map[ErrorKind]error{
+	KindUnknownOption:     ErrUnknownOption,
+	KindUnknownSubcommand: ErrUnknownSubcommand,
+	KindInvalidValue:      ErrInvalidValue,
+	KindRepeatedOption:    ErrRepeatedOption,
+	KindMissingRequired:   ErrMissingRequired,
+	KindValidation:        ErrValidation,
+	KindInvalidArgument:   ErrInvalidArgument,
+	KindConfig:            ErrConfig,
+	KindConflict:          ErrConflict,
+	KindAmbiguousOption:   ErrAmbiguousOption,
+}
+
+func (k ErrorKind) String() string {
+	if err, ok := kindErrors[k]; ok {
+		return err.Error()
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int(k))
+}
+
+// ValueSource 选项的值来自哪里
+type ValueSource int
+
+const (
+	SourceNone    ValueSource = iota //没有设置过
+	SourceDefault                    //default tag
+	SourceConfig                     //配置文件
+	SourceEnv                        //环境变量
+	SourceArgv                       //命令行
+	SourceImplied                    //其它选项的implies=
+)
+
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceConfig:
+		return "config"
+	case SourceEnv:
+		return "env"
+	case SourceArgv:
+		return "argv"
+	case SourceImplied:
+		return "implied"
+	}
+	return "none"
+}
+
+// ParseError 解析命令行, 环境变量, 配置文件, 数据校验时返回的错误
+type ParseError struct {
+	Kind   ErrorKind
+	Option string      //出错的选项, 比如--port, -p, <file>, 子命令名
+	Arg    string      //原始的参数或者值
+	Index  int         //在命令行里面的位置, 不是来自命令行的值为-1
+	Source ValueSource //出错的值来自哪里
+	Err    error       //原始错误
+
+	msg string
+}
+
+func (e *ParseError) Error() string {
+	return e.msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// errors.Is(err, clop.ErrUnknownOption) 这种写法可以判断错误类型
+func (e *ParseError) Is(target error) bool {
+	return target != nil && kindErrors[e.Kind] == target
+}
+
+func (c *Clop) unknownOptionErrorShort(optionName string, arg string, index int) error {
+	m := fmt.Sprintf(`error: Found argument '-%s' which wasn't expected, or isn't valid in this context%s`,
+		optionName, c.originMsg(index))
+
+	m += c.genMaybeHelpMsg(arg)
+	return &ParseError{Kind: KindUnknownOption, Option: "-" + optionName, Arg: c.args[index],
+		Index: c.argsOffset + index, Source: SourceArgv, msg: m}
+}
+
+func (c *Clop) unknownOptionError(optionName string, index int) error {
+	m := fmt.Sprintf(`error: Found argument '--%s' which wasn't expected, or isn't valid in this context%s`,
+		optionName, c.originMsg(index))
+
+	m += c.genMaybeHelpMsg(optionName)
+	if pos := strings.IndexByte(optionName, '='); pos != -1 {
+		optionName = optionName[:pos]
+	}
+	return &ParseError{Kind: KindUnknownOption, Option: "--" + optionName, Arg: c.args[index],
+		Index: c.argsOffset + index, Source: SourceArgv, msg: m}
+}
+
+func (c *Clop) unknownSubcommandError(name string, index int) error {
+	return &ParseError{Kind: KindUnknownSubcommand, Option: name, Arg: name, Index: c.argsOffset + index,
+		Source: SourceArgv, msg: fmt.Sprintf("Unknown subcommand:%s", name)}
+}
+
+func (c *Clop) invalidArgumentError(msg string, index int) error {
+	return &ParseError{Kind: KindInvalidArgument, Arg: c.args[index], Index: c.argsOffset + index,
+		Source: SourceArgv, msg: msg}
+}
+
+func (c *Clop) errOnce(optionName string, index int) error {
+	return &ParseError{Kind: KindRepeatedOption, Option: optionName, Arg: c.args[index], Index: c.argsOffset + index,
+		Source: SourceArgv,
+		msg:    fmt.Sprintf(`error: The argument '%s' was provided more than once, but cannot be used multiple times`, optionName)}
+}
+
+// 值转换失败的错误, 报错信息里面带上选项名
+func (c *Clop) invalidValueError(o *Option, val string, source ValueSource, index int, err error) error {
+	name := o.displayName()
+	pos := -1
+	from := ""
+	switch source {
+	case SourceArgv:
+		pos = c.argsOffset + index
+		from = c.originMsg(index)
+	case SourceEnv:
+		from = fmt.Sprintf(" (from env %s)", o.envName)
+	case SourceConfig:
+		from = fmt.Sprintf(" (from config key '%s')", o.getConfigKey())
+	}
+
+	return &ParseError{Kind: KindInvalidValue, Option: name, Arg: val, Index: pos, Source: source, Err: err,
+		msg: fmt.Sprintf("error: Invalid value '%s' for '%s'%s: %v", val, name, from, err)}
+}
+
+func configError(err error) error {
+	return &ParseError{Kind: KindConfig, Index: -1, Source: SourceConfig, Err: err, msg: err.Error()}
+}
+
+// 把validator的错误转成ParseError
+func validationError(fe validator.FieldError) error {
+	kind := KindValidation
+	if fe.Tag() == "required" {
+		kind = KindMissingRequired
+	}
+
+	name := strings.TrimPrefix(fe.Field(), "error: ")
+	return &ParseError{Kind: kind, Option: strings.Replace(name, ";", ",", -1), Arg: fmt.Sprint(fe.Value()),
+		Index: -1, Err: fe, msg: fe.Translate(valid.trans)}
+}
+
+// 报错信息里面使用的选项名, 优先使用长选项
+func (o *Option) displayName() string {
+	switch {
+	case len(o.showLong) > 0:
+		return "--" + o.showLong[0]
+	case len(o.showShort) > 0:
+		return "-" + o.showShort[0]
+	case len(o.argsName) > 0:
+		return "<" + o.argsName + ">"
+	}
+	return o.envName
+}
@@ -0,0 +1,82 @@
+package clop
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testParseError struct {
+	Port  int    `clop:"-p;--port;env=CLOP_ERR_PORT" usage:"port"`
+	Debug bool   `clop:"-d;--debug;once" usage:"debug"`
+	Name  string `clop:"-n;--name" usage:"name" valid:"required"`
+}
+
+func testBindError(args ...string) error {
+	got := testParseError{}
+	return New(args).SetExit(false).SetOutput(ioutil.Discard).Bind(&got)
+}
+
+func Test_ParseError_Kind(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		target error
+		kind   ErrorKind
+		option string
+		index  int
+	}{
+		{[]string{"-n", "a", "--unknown"}, ErrUnknownOption, KindUnknownOption, "--unknown", 2},
+		{[]string{"-n", "a", "--unknown=1"}, ErrUnknownOption, KindUnknownOption, "--unknown", 2},
+		{[]string{"-x"}, ErrUnknownOption, KindUnknownOption, "-x", 0},
+		{[]string{"-n", "a", "-d", "--debug"}, ErrRepeatedOption, KindRepeatedOption, "--debug", 3},
+		{[]string{"-n", "a", "--port", "abc"}, ErrInvalidValue, KindInvalidValue, "--port", 3},
+		{[]string{"-pabc"}, ErrInvalidValue, KindInvalidValue, "--port", 0},
+		{[]string{}, ErrMissingRequired, KindMissingRequired, "-n,--name", -1},
+	} {
+		err := testBindError(tc.args...)
+		assert.True(t, errors.Is(err, tc.target), "%v", tc.args)
+
+		var pe *ParseError
+		assert.True(t, errors.As(err, &pe), "%v", tc.args)
+		assert.Equal(t, tc.kind, pe.Kind, "%v", tc.args)
+		assert.Equal(t, tc.option, pe.Option, "%v", tc.args)
+		assert.Equal(t, tc.index, pe.Index, "%v", tc.args)
+	}
+}
+
+// 转换错误需要带上选项名, 并且可以拿到原始错误
+func Test_ParseError_InvalidValue(t *testing.T) {
+	err := testBindError("-n", "a", "--port", "abc")
+	assert.Contains(t, err.Error(), "'--port'")
+	assert.Contains(t, err.Error(), "'abc'")
+
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+
+	os.Setenv("CLOP_ERR_PORT", "xyz")
+	defer os.Unsetenv("CLOP_ERR_PORT")
+
+	err = testBindError("-n", "a")
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, SourceEnv, pe.Source)
+	assert.Equal(t, -1, pe.Index)
+	assert.Contains(t, err.Error(), "CLOP_ERR_PORT")
+}
+
+func Test_ParseError_Subcommand(t *testing.T) {
+	got := git{}
+	err := New([]string{"unknown"}).SetExit(false).SetOutput(ioutil.Discard).Bind(&got)
+	assert.True(t, errors.Is(err, ErrUnknownSubcommand))
+
+	// 子命令里面的错误, Index是在整个命令行里面的位置
+	err = New([]string{"add", "--xx"}).SetExit(false).SetOutput(ioutil.Discard).Bind(&got)
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 1, pe.Index)
+	assert.Equal(t, "--xx", pe.Arg)
+}
@@ -0,0 +1,86 @@
+package clop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+)
+
+var (
+	typeContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// 查找SubMain方法
+// SubMain()在Bind里面调用, SubMain(context.Context) error由Execute调用
+func subMainMethod(v reflect.Value) (subMain, subMainCtx reflect.Value) {
+	m := v.MethodByName(defaultSubMain)
+	if !m.IsValid() {
+		return
+	}
+
+	typ := m.Type()
+	switch {
+	case typ.NumIn() == 0 && typ.NumOut() == 0:
+		subMain = m
+	case typ.NumIn() == 1 && typ.In(0) == typeContext && typ.NumOut() == 1 && typ.Out(0) == typeError:
+		subMainCtx = m
+	}
+	return
+}
+
+// Execute 解析命令行, 然后调用选中的子命令的SubMain(context.Context) error方法
+// 没有选中子命令时, 调用x的SubMain(context.Context) error方法
+// 收到SIGINT, SIGTERM信号时, 取消传给SubMain的ctx
+// SubMain返回的错误按照ErrorHandling处理, 退出码见ExitCode
+func (c *Clop) Execute(ctx context.Context, x interface{}) error {
+	if err := c.Bind(x); err != nil {
+		return err
+	}
+
+	var subMainCtx reflect.Value
+	if c.selected != nil {
+		subMainCtx = c.selected.subMainCtx
+	} else {
+		_, subMainCtx = subMainMethod(reflect.ValueOf(x))
+	}
+
+	if !subMainCtx.IsValid() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	rv := subMainCtx.Call([]reflect.Value{reflect.ValueOf(ctx)})
+	err, _ := rv[0].Interface().(error)
+	if err == nil {
+		return nil
+	}
+
+	fmt.Fprintln(c.errW, err)
+	return c.applyErrorHandling(err)
+}
+
+// Execute 使用os.Args解析命令行并调用SubMain, 出错时按照ExitCode退出进程
+func Execute(ctx context.Context, x interface{}) {
+	CommandLine.SetProcName(os.Args[0])
+	if err := CommandLine.Execute(ctx, x); err != nil {
+		os.Exit(CommandLine.ExitCode(err))
+	}
+}
@@ -0,0 +1,129 @@
+package clop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type executeDeploy struct {
+	Env string `clop:"-e;--env" usage:"environment"`
+
+	err     error
+	running bool
+}
+
+func (d *executeDeploy) SubMain(ctx context.Context) error {
+	d.running = true
+	return d.err
+}
+
+type executeLegacy struct {
+	called bool
+}
+
+func (l *executeLegacy) SubMain() {
+	l.called = true
+}
+
+type executeWait struct {
+	canceled bool
+}
+
+// 给自己发送SIGINT, 等待ctx被取消
+func (w *executeWait) SubMain(ctx context.Context) error {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+
+	if err = p.Signal(os.Interrupt); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		w.canceled = true
+		return ctx.Err()
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+type executeRoot struct {
+	Debug  bool          `clop:"-d;--debug" usage:"debug mode"`
+	Deploy executeDeploy `clop:"subcommand=deploy" usage:"deploy"`
+	Legacy executeLegacy `clop:"subcommand=legacy" usage:"legacy SubMain"`
+	Wait   executeWait   `clop:"subcommand=wait" usage:"wait for signal"`
+
+	running bool
+}
+
+func (r *executeRoot) SubMain(ctx context.Context) error {
+	r.running = true
+	return nil
+}
+
+func Test_Execute(t *testing.T) {
+	got := executeRoot{}
+	err := New([]string{"deploy", "-e", "prod"}).SetExit(false).Execute(context.Background(), &got)
+	assert.NoError(t, err)
+	assert.True(t, got.Deploy.running)
+	assert.Equal(t, "prod", got.Deploy.Env)
+	assert.False(t, got.running)
+
+	// 没有选中子命令, 调用root的SubMain
+	got = executeRoot{}
+	assert.NoError(t, New([]string{"-d"}).SetExit(false).Execute(context.Background(), &got))
+	assert.True(t, got.running)
+	assert.False(t, got.Deploy.running)
+
+	// 不带参数的SubMain还是在Bind里面调用
+	got = executeRoot{}
+	assert.NoError(t, New([]string{"legacy"}).SetExit(false).Execute(context.Background(), &got))
+	assert.True(t, got.Legacy.called)
+	assert.False(t, got.running)
+
+	// Bind不调用SubMain(context.Context) error
+	got = executeRoot{}
+	assert.NoError(t, New([]string{"deploy"}).SetExit(false).Bind(&got))
+	assert.False(t, got.Deploy.running)
+}
+
+func Test_Execute_Error(t *testing.T) {
+	var errOut bytes.Buffer
+
+	got := executeRoot{}
+	got.Deploy.err = testExitCodeError{}
+	c := New([]string{"deploy"}).SetExit(false).SetErrOutput(&errOut)
+	err := c.Execute(context.Background(), &got)
+	assert.Equal(t, testExitCodeError{}, err)
+	assert.Equal(t, 3, c.ExitCode(err))
+	assert.Equal(t, "exit code error\n", errOut.String())
+
+	got = executeRoot{}
+	got.Deploy.err = errors.New("deploy failed")
+	assert.Panics(t, func() {
+		New([]string{"deploy"}).SetErrorHandling(PanicOnError).SetErrOutput(&errOut).Execute(context.Background(), &got)
+	})
+
+	got = executeRoot{}
+	c = New([]string{"-h"}).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.ErrorIs(t, c.Execute(context.Background(), &got), ErrHelp)
+	assert.False(t, got.running)
+}
+
+func Test_Execute_Signal(t *testing.T) {
+	got := executeRoot{}
+	err := New([]string{"wait"}).SetExit(false).SetErrOutput(&bytes.Buffer{}).Execute(context.Background(), &got)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		t.Skip(err)
+	}
+	assert.True(t, got.Wait.canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
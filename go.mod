@@ -1,11 +1,13 @@
 module github.com/guonaihong/clop
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/antlabs/strsim v0.0.2
 	github.com/go-playground/locales v0.14.0
 	github.com/go-playground/universal-translator v0.18.0
 	github.com/go-playground/validator/v10 v10.10.1
 	github.com/stretchr/testify v1.7.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 go 1.13
@@ -0,0 +1,246 @@
+package clop
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	groupExclusive = "exclusive" //组里面的选项最多只能设置一个
+	groupRequired  = "required"  //组里面的选项最少要设置一个
+	groupTogether  = "together"  //组里面的选项要么都设置, 要么都不设置
+)
+
+// 选项组, 来自group tag
+// group:"source;exclusive;required" 表示--file, --url, --stdin必须设置, 而且只能设置一个
+type optionGroup struct {
+	name      string
+	exclusive bool
+	required  bool
+	together  bool
+	options   []*Option
+}
+
+// 解析group和requires tag
+func (c *Clop) parseGroupTag(o *Option, sf reflect.StructField) error {
+	o.requires = splitOptionNames(Tag(sf.Tag).Get("requires"))
+
+	group := Tag(sf.Tag).Get("group")
+	if group == "" {
+		return nil
+	}
+
+	opts := strings.Split(group, ";")
+	name := strings.TrimSpace(opts[0])
+	if name == "" {
+		return fmt.Errorf("%s: %w: group name cannot be empty", sf.Name, ErrUnsupported)
+	}
+
+	var g *optionGroup
+	for _, v := range c.groups {
+		if v.name == name {
+			g = v
+			break
+		}
+	}
+
+	if g == nil {
+		g = &optionGroup{name: name}
+		c.groups = append(c.groups, g)
+	}
+
+	for _, opt := range opts[1:] {
+		// 同一个组的约束可以写在任意一个字段上
+		switch strings.TrimSpace(opt) {
+		case groupExclusive:
+			g.exclusive = true
+		case groupRequired:
+			g.required = true
+		case groupTogether:
+			g.together = true
+		case "":
+		default:
+			return fmt.Errorf("%s: %w:group:%s", sf.Name, ErrUnsupported, opt)
+		}
+	}
+
+	g.options = append(g.options, o)
+	return nil
+}
+
+// 报错信息和Usage行里面使用的选项名
+func (c *Clop) groupOptionName(o *Option) string {
+	if name := c.showShortAndLong(o); name != "" {
+		return name
+	}
+	return o.displayName()
+}
+
+// 根据名字查找选项, 可以是长短选项或者args名
+func (c *Clop) lookupOption(name string) *Option {
+	if o, ok := c.shortAndLong[name]; ok {
+		return o
+	}
+
+	for _, o := range c.envAndArgs {
+		if o.argsName == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// 注册结束之后检查requires, conflicts, implies引用的选项是否存在, 包括所有的子命令
+// 写错的名字在注册时就报错, 不用等到设置了这个选项才发现
+func (c *Clop) checkOptionNames() error {
+	for _, o := range c.sortedOptions() {
+		for _, name := range o.requires {
+			if c.lookupOption(name) == nil {
+				return fmt.Errorf("%s: requires: %w: %s", o.displayName(), ErrNotFoundName, name)
+			}
+		}
+
+		for _, name := range o.conflicts {
+			if c.lookupOption(name) == nil {
+				return fmt.Errorf("%s: conflicts: %w: %s", o.displayName(), ErrNotFoundName, name)
+			}
+		}
+
+		for _, imp := range o.implies {
+			if c.lookupOption(imp.name) == nil {
+				return fmt.Errorf("%s: implies: %w: %s", o.displayName(), ErrNotFoundName, imp.name)
+			}
+		}
+	}
+
+	used := make(map[*Subcommand]struct{}, len(c.subcommand))
+	for _, sub := range c.subcommand {
+		if _, ok := used[sub]; ok {
+			continue
+		}
+		used[sub] = struct{}{}
+
+		if err := sub.Clop.checkOptionNames(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Clop) groupError(kind ErrorKind, o *Option, msg string) error {
+	index := -1
+	if o.source == SourceArgv {
+		index = c.argsOffset + int(o.index>>31)
+	}
+
+	return &ParseError{Kind: kind, Option: o.displayName(), Index: index, Source: o.source, msg: msg}
+}
+
+// 互斥的选项同时出现, 报错时指向后出现的选项
+func (c *Clop) conflictError(a, b *Option) error {
+	if a.index > b.index {
+		a, b = b, a
+	}
+
+	return c.groupError(KindConflict, b, fmt.Sprintf("error: The argument '%s' cannot be used with '%s'",
+		c.groupOptionName(b), c.groupOptionName(a)))
+}
+
+// 检查选项组, requires tag和conflicts=, 在当层的选项都绑定之后调用
+func (c *Clop) checkGroups() error {
+	for _, g := range c.groups {
+		var set []*Option
+		for _, o := range g.options {
+			if o.cmdSet {
+				set = append(set, o)
+			}
+		}
+
+		names := make([]string, 0, len(g.options))
+		for _, o := range g.options {
+			names = append(names, c.groupOptionName(o))
+		}
+
+		if g.required && len(set) == 0 {
+			return &ParseError{Kind: KindMissingRequired, Option: g.name, Index: -1,
+				msg: fmt.Sprintf("error: One of the following arguments must be provided: %s", strings.Join(names, " | "))}
+		}
+
+		if g.exclusive && len(set) > 1 {
+			return c.conflictError(set[0], set[1])
+		}
+
+		if g.together && len(set) > 0 && len(set) < len(g.options) {
+			for _, o := range g.options {
+				if !o.cmdSet {
+					return c.groupError(KindMissingRequired, set[0], fmt.Sprintf("error: The argument '%s' requires '%s'",
+						c.groupOptionName(set[0]), c.groupOptionName(o)))
+				}
+			}
+		}
+	}
+
+	used := make(map[*Option]struct{}, len(c.shortAndLong))
+	check := func(o *Option) error {
+		if _, ok := used[o]; ok || !o.cmdSet {
+			return nil
+		}
+		used[o] = struct{}{}
+
+		// requires和conflicts的名字在注册时已经由checkOptionNames检查过
+		for _, name := range o.requires {
+			need := c.lookupOption(name)
+			if !need.cmdSet {
+				return c.groupError(KindMissingRequired, o, fmt.Sprintf("error: The argument '%s' requires '%s'",
+					c.groupOptionName(o), c.groupOptionName(need)))
+			}
+		}
+
+		for _, name := range o.conflicts {
+			other := c.lookupOption(name)
+			if other.cmdSet {
+				return c.conflictError(o, other)
+			}
+		}
+		return nil
+	}
+
+	for _, o := range c.shortAndLong {
+		if err := check(o); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range c.envAndArgs {
+		if err := check(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Usage行里面显示的选项组约束
+// 必须设置的组用(), 可选的组用[], 互斥的选项用|分隔
+func (c *Clop) genGroupUsage() (usage []string) {
+	for _, g := range c.groups {
+		names := make([]string, 0, len(g.options))
+		for _, o := range g.options {
+			names = append(names, o.displayName())
+		}
+
+		sep := " "
+		if !g.together {
+			sep = "|"
+		}
+
+		s := strings.Join(names, sep)
+		if g.required {
+			s = "(" + s + ")"
+		} else {
+			s = "[" + s + "]"
+		}
+		usage = append(usage, s)
+	}
+	return usage
+}
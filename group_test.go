@@ -0,0 +1,102 @@
+package clop
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type groupSource struct {
+	File     string `clop:"-f;--file" usage:"read from file" group:"source;exclusive;required"`
+	URL      string `clop:"--url" usage:"read from url" group:"source"`
+	Stdin    bool   `clop:"--stdin" usage:"read from stdin" group:"source"`
+	User     string `clop:"-u;--user" usage:"user" group:"auth;together"`
+	Password string `clop:"--password;env=CLOP_GROUP_PASSWORD" usage:"password" group:"auth"`
+	Token    string `clop:"--token" usage:"token" requires:"url"`
+}
+
+func testGroupBind(args ...string) (groupSource, error) {
+	got := groupSource{}
+	err := New(args).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&got)
+	return got, err
+}
+
+func Test_Group_Exclusive(t *testing.T) {
+	got, err := testGroupBind("--url", "http://a")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://a", got.URL)
+
+	_, err = testGroupBind()
+	assert.ErrorIs(t, err, ErrMissingRequired)
+	assert.Equal(t, "error: One of the following arguments must be provided: -f,--file | --url | --stdin", err.Error())
+
+	// 报错指向后出现的选项
+	_, err = testGroupBind("--stdin", "--file=a.txt")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, "error: The argument '-f,--file' cannot be used with '--stdin'", err.Error())
+
+	pe := err.(*ParseError)
+	assert.Equal(t, "--file", pe.Option)
+	assert.Equal(t, 1, pe.Index)
+
+	type groupEmptyName struct {
+		File string `clop:"-f;--file" usage:"file" group:";exclusive"`
+	}
+	assert.ErrorIs(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&groupEmptyName{}), ErrUnsupported)
+}
+
+func Test_Group_Together(t *testing.T) {
+	got, err := testGroupBind("--stdin", "-u", "admin", "--password", "123")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", got.Password)
+
+	_, err = testGroupBind("--stdin", "-u", "admin")
+	assert.ErrorIs(t, err, ErrMissingRequired)
+	assert.Equal(t, "error: The argument '-u,--user' requires '--password'", err.Error())
+
+	// 环境变量设置的值也算
+	os.Setenv("CLOP_GROUP_PASSWORD", "123")
+	defer os.Unsetenv("CLOP_GROUP_PASSWORD")
+	_, err = testGroupBind("--stdin", "-u", "admin")
+	assert.NoError(t, err)
+}
+
+func Test_Group_Requires(t *testing.T) {
+	_, err := testGroupBind("--url", "http://a", "--token", "t")
+	assert.NoError(t, err)
+
+	_, err = testGroupBind("--stdin", "--token", "t")
+	assert.ErrorIs(t, err, ErrMissingRequired)
+	assert.Equal(t, "error: The argument '--token' requires '--url'", err.Error())
+}
+
+type groupTypoRequires struct {
+	User     string `clop:"--user" requires:"pasword" usage:"user"`
+	Password string `clop:"--password" usage:"password"`
+}
+
+type groupTypoSub struct {
+	Login groupTypoRequires `clop:"subcommand=login" usage:"login"`
+}
+
+// requires写错的名字在注册时就报错, 不管有没有设置这个选项
+func Test_Group_RequiresNotFound(t *testing.T) {
+	for _, x := range []interface{}{&groupTypoRequires{}, &groupTypoSub{}} {
+		assert.ErrorIs(t, New(nil).Register(x), ErrNotFoundName)
+		assert.ErrorIs(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(x), ErrNotFoundName)
+	}
+
+	var user string
+	c := New(nil).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "user", Requires: []string{"pasword"}, Target: &user}))
+	assert.ErrorIs(t, c.Parse(), ErrNotFoundName)
+}
+
+func Test_Group_Usage(t *testing.T) {
+	var out bytes.Buffer
+	c := New([]string{"-h"}).SetProcName("fetch").SetExit(false).SetOutput(&out)
+	assert.ErrorIs(t, c.Bind(&groupSource{}), ErrHelp)
+	assert.Contains(t, out.String(), "fetch [Flags] [Options] (--file|--url|--stdin) [--user --password]")
+}
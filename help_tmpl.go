@@ -41,6 +41,7 @@ type Help struct {
 	Args             []showOption
 	Envs             []showOption
 	Subcommand       []showOption
+	Groups           []string //选项组的约束, 显示在Usage行
 	MaxNameLen       int
 	ShowUsageDefault bool
 }
@@ -69,6 +70,7 @@ var usageDefaultTmpl = `{{- $ShowUsageDefault := .ShowUsageDefault}}{{- if gt (l
     {{if gt (len .ProcessName) 0}}{{.ProcessName}} {{end}}
 {{- if gt (len .Flags) 0}}[Flags] {{end}}
 {{- if gt (len .Options) 0}}[Options] {{end}}
+{{- range $_, $group := .Groups}}{{$group}} {{end}}
 {{- range $_, $flag := .Args}}{{$flag.Opt}} {{end}}
 {{- if gt (len .Subcommand) 0}}<Subcommand> {{end}}
 {{- end}}
@@ -0,0 +1,93 @@
+package clop
+
+import (
+	"sort"
+	"strings"
+)
+
+// implies=verbose=true,level=3 里面的一项
+type impliedValue struct {
+	name  string
+	value string
+}
+
+// 解析implies=后面的内容, 没有值的选项当作bool类型, 值是true
+func parseImplies(s string) (implies []impliedValue) {
+	for _, kv := range strings.Split(s, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+
+		value := "true"
+		if pos := strings.IndexByte(kv, '='); pos != -1 {
+			kv, value = kv[:pos], kv[pos+1:]
+		}
+		implies = append(implies, impliedValue{name: strings.TrimLeft(kv, "-"), value: value})
+	}
+	return implies
+}
+
+// 逗号分隔的选项名, 可以带-或者--
+func splitOptionNames(s string) (names []string) {
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimLeft(strings.TrimSpace(name), "-"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// 设置过的选项顺带设置implies=里面的选项
+// 命令行, 环境变量, 配置文件显式设置过的选项不会被覆盖
+// 支持a implies b, b implies c这种链式的写法
+func (c *Clop) applyImplies() error {
+	var all []*Option
+	used := make(map[*Option]struct{}, len(c.shortAndLong))
+	add := func(o *Option) {
+		if _, ok := used[o]; ok || len(o.implies) == 0 {
+			return
+		}
+		used[o] = struct{}{}
+		all = append(all, o)
+	}
+
+	for _, o := range c.shortAndLong {
+		add(o)
+	}
+
+	for _, o := range c.envAndArgs {
+		add(o)
+	}
+
+	// 多个选项implies同一个选项时, 后出现的优先
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].index > all[j].index
+	})
+
+	done := make(map[*Option]struct{}, len(all))
+	for changed := true; changed; {
+		changed = false
+		for _, o := range all {
+			if _, ok := done[o]; ok || !o.cmdSet || o.pointer.IsZero() {
+				continue
+			}
+			done[o] = struct{}{}
+			changed = true
+
+			// implies的名字在注册时已经由checkOptionNames检查过
+			for _, imp := range o.implies {
+				target := c.lookupOption(imp.name)
+				if target.cmdSet {
+					continue
+				}
+
+				if err := c.setValueAndIndex(imp.value, target, SourceImplied, 0, 0); err != nil {
+					return err
+				}
+				target.index = o.index
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package clop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type impliesLs struct {
+	All     bool   `clop:"-a;--all;implies=verbose,level=3" usage:"show all"`
+	Verbose bool   `clop:"-v;--verbose;implies=color=auto" usage:"verbose"`
+	Level   int    `clop:"-l;--level" usage:"level" default:"1"`
+	Color   string `clop:"--color" usage:"color"`
+	JSON    bool   `clop:"--json;conflicts=table,csv" usage:"json output"`
+	Table   bool   `clop:"--table" usage:"table output"`
+	CSV     bool   `clop:"--csv" usage:"csv output"`
+}
+
+func Test_Implies(t *testing.T) {
+	got := impliesLs{}
+	assert.NoError(t, New([]string{"-a"}).SetExit(false).Bind(&got))
+	assert.Equal(t, impliesLs{All: true, Verbose: true, Level: 3, Color: "auto"}, got)
+
+	// 命令行显式设置的值优先
+	got = impliesLs{}
+	assert.NoError(t, New([]string{"--level", "5", "-a", "--verbose=false"}).SetExit(false).Bind(&got))
+	assert.Equal(t, impliesLs{All: true, Verbose: false, Level: 5}, got)
+
+	got = impliesLs{}
+	assert.NoError(t, New([]string{"--all=false"}).SetExit(false).Bind(&got))
+	assert.Equal(t, impliesLs{Level: 1}, got)
+}
+
+func Test_Conflicts(t *testing.T) {
+	got := impliesLs{}
+	assert.NoError(t, New([]string{"--json"}).SetExit(false).Bind(&got))
+	assert.True(t, got.JSON)
+
+	// 报错指向后出现的选项
+	err := New([]string{"--json", "--table"}).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&impliesLs{})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, "error: The argument '--table' cannot be used with '--json'", err.Error())
+	assert.Equal(t, 1, err.(*ParseError).Index)
+
+	err = New([]string{"--csv", "--json"}).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&impliesLs{})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, "error: The argument '--json' cannot be used with '--csv'", err.Error())
+	assert.Equal(t, "--json", err.(*ParseError).Option)
+}
+
+type impliesTypoConflicts struct {
+	JSON bool `clop:"--json;conflicts=tabel" usage:"json output"`
+}
+
+type impliesTypoImplies struct {
+	All bool `clop:"--all;implies=verbos" usage:"show all"`
+}
+
+type impliesTypoSub struct {
+	Ls impliesTypoImplies `clop:"subcommand=ls" usage:"list"`
+}
+
+// conflicts, implies写错的名字在注册时就报错, 不管有没有设置这个选项
+func Test_Implies_NotFoundName(t *testing.T) {
+	for _, x := range []interface{}{&impliesTypoConflicts{}, &impliesTypoImplies{}, &impliesTypoSub{}} {
+		assert.ErrorIs(t, New(nil).Register(x), ErrNotFoundName)
+		assert.ErrorIs(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(x), ErrNotFoundName)
+	}
+
+	var all bool
+	c := New(nil).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "all", Tag: "implies=verbos", Target: &all}))
+	assert.ErrorIs(t, c.Parse(), ErrNotFoundName)
+}
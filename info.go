@@ -0,0 +1,132 @@
+package clop
+
+import (
+	"sort"
+	"strings"
+)
+
+// OptionInfo 选项的信息和当前值的来源, 由Lookup, Visit, VisitAll返回
+type OptionInfo struct {
+	Name    string      //显示的选项名, 比如--port, -p, <file>, 只有环境变量的选项是环境变量名
+	Short   []string    //短选项名, 不带-
+	Long    []string    //长选项名, 不带--
+	Args    string      //args名
+	Env     string      //环境变量名
+	Default string      //default tag
+	Usage   string      //帮助信息
+	Field   string      //Go字段的路径, 比如Serve.Workers
+	Source  ValueSource //值来自哪里
+	Raw     string      //最后一次设置的原始字符串
+	Index   int         //值在命令行里面的位置, 不是来自命令行的值为-1
+	Value   interface{} //当前的值
+}
+
+// 注册时记录字段路径, 返回的函数用于注册结束时出栈
+func (c *Clop) pushFieldPath(name string) func() {
+	root := c.getRoot()
+	n := len(root.fieldPath)
+	root.fieldPath = append(root.fieldPath, name)
+	return func() {
+		root.fieldPath = root.fieldPath[:n]
+	}
+}
+
+func (o *Option) info() *OptionInfo {
+	return &OptionInfo{
+		Name:    o.displayName(),
+		Short:   append([]string(nil), o.showShort...),
+		Long:    append([]string(nil), o.showLong...),
+		Args:    o.argsName,
+		Env:     o.envName,
+		Default: o.showDefValue,
+		Usage:   o.usage,
+		Field:   o.fieldPath,
+		Source:  o.source,
+		Raw:     o.rawValue,
+		Index:   o.argvPos,
+		Value:   o.pointer.Interface(),
+	}
+}
+
+// Lookup 查找当前命令的选项, name可以是短选项, 长选项(带不带-都可以), args名或者环境变量名
+// 子命令的选项使用GetSubcommand(path...).Lookup(name)
+// 没有找到返回nil
+func (c *Clop) Lookup(name string) *OptionInfo {
+	if o := c.lookupInfo(name); o != nil {
+		return o.info()
+	}
+	return nil
+}
+
+func (c *Clop) lookupInfo(name string) *Option {
+	if o, ok := c.shortAndLong[strings.TrimLeft(name, "-")]; ok && o.pointer.IsValid() {
+		if o.negate != nil {
+			return o.negate
+		}
+		return o
+	}
+
+	for _, o := range c.envAndArgs {
+		if o.argsName != "" && (name == o.argsName || name == "<"+o.argsName+">") || o.envName != "" && name == o.envName {
+			return o
+		}
+	}
+	return nil
+}
+
+// 当前命令的所有选项, 按照选项名的字典序排列
+func (c *Clop) sortedOptions() []*Option {
+	used := make(map[*Option]struct{}, len(c.shortAndLong))
+	options := make([]*Option, 0, len(c.shortAndLong)+len(c.envAndArgs))
+	add := func(o *Option) {
+		if _, ok := used[o]; ok || !o.pointer.IsValid() || o.negate != nil {
+			return
+		}
+		used[o] = struct{}{}
+		options = append(options, o)
+	}
+
+	for _, o := range c.shortAndLong {
+		add(o)
+	}
+
+	for _, o := range c.envAndArgs {
+		add(o)
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return strings.TrimLeft(options[i].displayName(), "-<") < strings.TrimLeft(options[j].displayName(), "-<")
+	})
+	return options
+}
+
+// VisitAll 按照选项名的字典序访问当前命令的所有选项
+func (c *Clop) VisitAll(fn func(*OptionInfo)) {
+	for _, o := range c.sortedOptions() {
+		fn(o.info())
+	}
+}
+
+// Visit 按照选项名的字典序访问当前命令设置过的选项, 来自命令行, 环境变量, 配置文件或者implies=, 不包括默认值
+func (c *Clop) Visit(fn func(*OptionInfo)) {
+	for _, o := range c.sortedOptions() {
+		if o.source > SourceDefault {
+			fn(o.info())
+		}
+	}
+}
+
+// Lookup 查找CommandLine的选项
+func Lookup(name string) *OptionInfo {
+	return CommandLine.Lookup(name)
+}
+
+// VisitAll 访问CommandLine的所有选项
+func VisitAll(fn func(*OptionInfo)) {
+	CommandLine.VisitAll(fn)
+}
+
+// Visit 访问CommandLine设置过的选项
+func Visit(fn func(*OptionInfo)) {
+	CommandLine.Visit(fn)
+}
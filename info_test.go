@@ -0,0 +1,85 @@
+package clop
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testInfoServe struct {
+	Workers int `clop:"-w;--workers" usage:"number of workers" default:"4"`
+}
+
+type testInfoLog struct {
+	Level string `clop:"--level" usage:"log level" default:"info"`
+}
+
+type testInfo struct {
+	Port  int           `clop:"-p;--port;env=CLOP_INFO_PORT" usage:"port" default:"80"`
+	Host  string        `clop:"--host" usage:"host"`
+	Color bool          `clop:"--color;negatable" usage:"color"`
+	Log   testInfoLog   `usage:"-"`
+	File  string        `clop:"args=file" usage:"file"`
+	Serve testInfoServe `clop:"subcommand=serve" usage:"start server"`
+}
+
+func Test_Info_Lookup(t *testing.T) {
+	os.Setenv("CLOP_INFO_PORT", "8080")
+	defer os.Unsetenv("CLOP_INFO_PORT")
+
+	got := testInfo{}
+	c := New([]string{"a.txt", "--no-color", "serve", "-w", "8"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+
+	port := c.Lookup("port")
+	assert.Equal(t, &OptionInfo{Name: "--port", Short: []string{"p"}, Long: []string{"port"}, Env: "CLOP_INFO_PORT",
+		Default: "80", Usage: "port", Field: "Port", Source: SourceEnv, Raw: "8080", Index: -1, Value: 8080}, port)
+	assert.Equal(t, port, c.Lookup("-p"))
+	assert.Equal(t, port, c.Lookup("CLOP_INFO_PORT"))
+
+	level := c.Lookup("--level")
+	assert.Equal(t, "Log.Level", level.Field)
+	assert.Equal(t, SourceDefault, level.Source)
+	assert.Equal(t, "info", level.Raw)
+
+	file := c.Lookup("file")
+	assert.Equal(t, SourceArgv, file.Source)
+	assert.Equal(t, 0, file.Index)
+
+	// --no-color返回--color的信息
+	color := c.Lookup("no-color")
+	assert.Equal(t, "--color", color.Name)
+	assert.Equal(t, SourceArgv, color.Source)
+	assert.Equal(t, 1, color.Index)
+	assert.Equal(t, false, color.Value)
+
+	assert.Equal(t, SourceNone, c.Lookup("host").Source)
+	assert.Nil(t, c.Lookup("workers"))
+	assert.Nil(t, c.Lookup("help"))
+
+	// 子命令的选项, Index是整个命令行里面的位置
+	workers := c.GetSubcommand("serve").Lookup("w")
+	assert.Equal(t, "Serve.Workers", workers.Field)
+	assert.Equal(t, SourceArgv, workers.Source)
+	assert.Equal(t, "8", workers.Raw)
+	assert.Equal(t, 4, workers.Index)
+}
+
+func Test_Info_Visit(t *testing.T) {
+	got := testInfo{}
+	c := New([]string{"--host", "a.com", "b.txt"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+
+	var all []string
+	c.VisitAll(func(o *OptionInfo) {
+		all = append(all, o.Name)
+	})
+	assert.Equal(t, []string{"--color", "<file>", "--host", "--level", "--port"}, all)
+
+	var set []string
+	c.Visit(func(o *OptionInfo) {
+		set = append(set, o.Name+"="+o.Raw)
+	})
+	assert.Equal(t, []string{"<file>=b.txt", "--host=a.com"}, set)
+}
@@ -0,0 +1,70 @@
+package clop
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMap struct {
+	Set     map[string]string        `clop:"-D;--set;env=CLOP_MAP_SET" usage:"set variables"`
+	Limits  map[string]int           `clop:"--limit" usage:"limits" default:"cpu=2,mem=4"`
+	Timeout map[string]time.Duration `clop:"--timeout" usage:"timeouts"`
+	Weights map[int]float64          `clop:"--weight" usage:"weights"`
+	Labels  map[string]string        `clop:"--labels;sep=&" usage:"labels"`
+}
+
+func Test_Map(t *testing.T) {
+	got := testMap{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, testMap{Limits: map[string]int{"cpu": 2, "mem": 4}}, got)
+
+	got = testMap{}
+	args := []string{"-D", "env=prod", "-D", "region=eu=west", "--limit", "cpu=8", "--timeout", "read=1s", "--weight", "1=0.5", "--set", "a,b=c", "--labels", "a=1&b=2"}
+	assert.NoError(t, New(args).SetExit(false).Bind(&got))
+	assert.Equal(t, testMap{
+		Set:     map[string]string{"env": "prod", "region": "eu=west", "a,b": "c"},
+		Limits:  map[string]int{"cpu": 8},
+		Timeout: map[string]time.Duration{"read": time.Second},
+		Weights: map[int]float64{1: 0.5},
+		Labels:  map[string]string{"a": "1", "b": "2"},
+	}, got)
+
+	// json格式还是整个解析
+	got = testMap{}
+	assert.NoError(t, New([]string{"--limit", `{"disk":1}`}).SetExit(false).Bind(&got))
+	assert.Equal(t, map[string]int{"disk": 1}, got.Limits)
+}
+
+func Test_Map_Env(t *testing.T) {
+	os.Setenv("CLOP_MAP_SET", `a=1,b=2\,3`)
+	defer os.Unsetenv("CLOP_MAP_SET")
+
+	got := testMap{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, map[string]string{"a": "1", "b": "2,3"}, got.Set)
+}
+
+func Test_Map_Error(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		msg  string
+	}{
+		{[]string{"-D", "env"}, "error: Invalid value 'env' for '--set': expected key=value"},
+		{[]string{"--limit", "cpu=x"}, `error: Invalid value 'cpu=x' for '--limit': invalid value 'x': strconv.ParseInt: parsing "x": invalid syntax`},
+		{[]string{"--weight", "a=1"}, `error: Invalid value 'a=1' for '--weight': invalid key 'a': strconv.ParseInt: parsing "a": invalid syntax`},
+	} {
+		err := New(tc.args).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&testMap{})
+		assert.ErrorIs(t, err, ErrInvalidValue)
+		assert.EqualError(t, err, tc.msg)
+	}
+}
+
+func Test_Map_Help(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testMap{}), ErrHelp)
+	assert.Contains(t, out.String(), "-D,--set <key=value>")
+}
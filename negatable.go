@@ -0,0 +1,25 @@
+package clop
+
+import "fmt"
+
+const negatablePrefix = "no-"
+
+// 给negatable的bool选项注册--no-<long>
+func (c *Clop) registerNegatable(o *Option) error {
+	if !o.negatable {
+		return nil
+	}
+
+	if len(o.showLong) == 0 {
+		return fmt.Errorf("%s: %w: negatable requires a long option", c.showShortAndLong(o), ErrUnsupported)
+	}
+
+	for _, long := range o.showLong {
+		name := negatablePrefix + long
+		neg := &Option{pointer: o.pointer, usage: o.usage, negate: o, showLong: []string{name}}
+		if err := c.setOption(name, neg, c.shortAndLong, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package clop
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNegatable struct {
+	Color bool `clop:"-c;--color;negatable;env=CLOP_NEGATABLE_COLOR" usage:"colorize output" default:"true"`
+	Pager bool `clop:"--pager;negatable" usage:"use pager"`
+}
+
+func Test_Negatable(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		need testNegatable
+	}{
+		{nil, testNegatable{Color: true}},
+		{[]string{"--no-color"}, testNegatable{}},
+		{[]string{"--no-color", "--color"}, testNegatable{Color: true}},
+		{[]string{"--color", "--no-color", "--pager"}, testNegatable{Pager: true}},
+		{[]string{"--no-color=false"}, testNegatable{Color: true}},
+		{[]string{"--pager", "--no-pager"}, testNegatable{Color: true}},
+	} {
+		got := testNegatable{}
+		assert.NoError(t, New(tc.args).SetExit(false).Bind(&got), tc.args)
+		assert.Equal(t, tc.need, got, tc.args)
+	}
+}
+
+// 命令行的--no-color比环境变量优先
+func Test_Negatable_Env(t *testing.T) {
+	os.Setenv("CLOP_NEGATABLE_COLOR", "true")
+	defer os.Unsetenv("CLOP_NEGATABLE_COLOR")
+
+	got := testNegatable{}
+	assert.NoError(t, New([]string{"--no-color"}).SetExit(false).Bind(&got))
+	assert.False(t, got.Color)
+}
+
+func Test_Negatable_Help(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testNegatable{}), ErrHelp)
+	assert.Contains(t, out.String(), "-c,--[no-]color")
+	assert.Contains(t, out.String(), "--[no-]pager")
+	assert.NotContains(t, out.String(), "--no-color")
+
+	type negatableInt struct {
+		N int `clop:"-n;--num;negatable" usage:"number"`
+	}
+	assert.ErrorIs(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&negatableInt{}), ErrUnsupportedType)
+
+	type negatableShort struct {
+		C bool `clop:"-c;negatable" usage:"color"`
+	}
+	assert.ErrorIs(t, New(nil).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&negatableShort{}), ErrUnsupported)
+}
@@ -0,0 +1,44 @@
+package clop
+
+import "sort"
+
+// Occurrence 命令行里面的一次选项或者参数
+type Occurrence struct {
+	Option string   //选项名, 比如--output, --no-color, <file>, 优先使用长选项
+	Value  string   //原始值, bool选项和计数选项是true
+	Index  int      //值在整个命令行里面的位置
+	Offset int      //值在短选项组合里面的开始位置, 不算-, 比如-ltr里面的t是1, -lofile里面的file是2, 其它情况是0
+	Path   []string //所在的子命令路径, root的选项是nil
+}
+
+// 记录命令行里面的每一次设置, 只在root上保存
+func (c *Clop) addOccurrence(o *Option, val string, index int, lowIndex int) {
+	root := c.getRoot()
+	root.occurrences = append(root.occurrences, Occurrence{
+		Option: o.displayName(),
+		Value:  val,
+		Index:  c.argsOffset + index,
+		Offset: lowIndex,
+		Path:   c.subcommandPath(),
+	})
+}
+
+// Occurrences 按照命令行里面的顺序返回所有设置过的选项和参数, 包括子命令
+// 重复出现的选项每次都会记录, 可以用来实现find -o a -x -o b这种和顺序相关的表达式
+func (c *Clop) Occurrences() []Occurrence {
+	root := c.getRoot()
+	occurrences := append([]Occurrence(nil), root.occurrences...)
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		return a.Offset < b.Offset
+	})
+	return occurrences
+}
+
+// Occurrences 返回CommandLine的命令行里面设置过的选项和参数
+func Occurrences() []Occurrence {
+	return CommandLine.Occurrences()
+}
@@ -0,0 +1,71 @@
+package clop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testOccurrenceAdd struct {
+	Force bool   `clop:"-f;--force" usage:"force"`
+	Name  string `clop:"args=name" usage:"name"`
+}
+
+type testOccurrence struct {
+	Long    bool     `clop:"-l" usage:"long"`
+	Time    bool     `clop:"-t" usage:"sort by time"`
+	Output  []string `clop:"-o;--output" usage:"output"`
+	Exclude bool     `clop:"-x;--exclude" usage:"exclude"`
+	Color   bool     `clop:"--color;negatable" usage:"color"`
+	Files   []string `clop:"args=files" usage:"files"`
+}
+
+// 重复和交错出现的选项按照命令行的顺序记录
+func Test_Occurrences(t *testing.T) {
+	got := testOccurrence{}
+	c := New([]string{"-lt", "-o", "a", "a.txt", "-x", "--output=b", "--no-color"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, []Occurrence{
+		{Option: "-l", Value: "true", Index: 0, Offset: 0},
+		{Option: "-t", Value: "true", Index: 0, Offset: 1},
+		{Option: "--output", Value: "a", Index: 2},
+		{Option: "<files>", Value: "a.txt", Index: 3},
+		{Option: "--exclude", Value: "true", Index: 4},
+		{Option: "--output", Value: "b", Index: 5},
+		{Option: "--no-color", Value: "true", Index: 6},
+	}, c.Occurrences())
+}
+
+// 短选项组合里面带值的选项, Offset是值的开始位置
+func Test_Occurrences_ShortCluster(t *testing.T) {
+	got := testOccurrence{}
+	c := New([]string{"-ltofile", "-xobb", "-lo", "c"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, []Occurrence{
+		{Option: "-l", Value: "true", Index: 0, Offset: 0},
+		{Option: "-t", Value: "true", Index: 0, Offset: 1},
+		{Option: "--output", Value: "file", Index: 0, Offset: 3},
+		{Option: "--exclude", Value: "true", Index: 1, Offset: 0},
+		{Option: "--output", Value: "bb", Index: 1, Offset: 2},
+		{Option: "-l", Value: "true", Index: 2, Offset: 0},
+		{Option: "--output", Value: "c", Index: 3, Offset: 0},
+	}, c.Occurrences())
+}
+
+type testOccurrenceGit struct {
+	Long bool              `clop:"-l" usage:"long"`
+	Add  testOccurrenceAdd `clop:"subcommand=add" usage:"add"`
+}
+
+// 子命令的选项带上子命令路径, 位置是整个命令行里面的位置
+func Test_Occurrences_Subcommand(t *testing.T) {
+	got := testOccurrenceGit{}
+	c := New([]string{"-l", "add", "-f", "x"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, []Occurrence{
+		{Option: "-l", Value: "true", Index: 0},
+		{Option: "--force", Value: "true", Index: 2, Path: []string{"add"}},
+		{Option: "<name>", Value: "x", Index: 3, Path: []string{"add"}},
+	}, c.Occurrences())
+	assert.Equal(t, c.Occurrences(), c.GetSubcommand("add").Occurrences())
+}
@@ -0,0 +1,28 @@
+package clop
+
+import "reflect"
+
+// 延迟分配的指针字段
+// 命令行, 环境变量, 配置或者默认值设置过值, 才把ptr赋值给field, 否则field保持nil
+type lazyPtr struct {
+	field reflect.Value //nil指针字段
+	ptr   reflect.Value //预先分配的指针
+}
+
+// 注册nil指针字段, 返回的函数用于注册结束时出栈
+func (c *Clop) pushLazyPtr(field reflect.Value) func() {
+	n := len(c.lazy)
+	c.lazy = append(c.lazy, lazyPtr{field: field, ptr: reflect.New(field.Type().Elem())})
+	return func() {
+		c.lazy = c.lazy[:n]
+	}
+}
+
+// 从外到内给指针字段赋值
+func allocLazyPtrs(lazy []lazyPtr) {
+	for _, l := range lazy {
+		if l.field.IsNil() {
+			l.field.Set(l.ptr)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package clop
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPointerTLS struct {
+	Cert string `clop:"--cert" usage:"cert file"`
+	Key  string `clop:"--key" usage:"key file"`
+}
+
+type testPointerLog struct {
+	Level string `clop:"--log-level" usage:"log level" default:"info"`
+}
+
+type testPointerServe struct {
+	Port *int `clop:"-p;--port" usage:"port"`
+}
+
+type testPointer struct {
+	Retries *int            `clop:"-r;--retries" usage:"retries"`
+	Debug   *bool           `clop:"-d;--debug;env=CLOP_POINTER_DEBUG" usage:"debug"`
+	Timeout *time.Duration  `clop:"--timeout" usage:"timeout" default:"1s"`
+	Hosts   *[]string       `clop:"--host" usage:"hosts"`
+	Name    *string         `clop:"args=name" usage:"name"`
+	TLS     *testPointerTLS `usage:"tls"`
+	Log     *testPointerLog
+	Serve   *testPointerServe `clop:"subcommand=serve" usage:"serve"`
+}
+
+func Test_Pointer(t *testing.T) {
+	got := testPointer{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Nil(t, got.Retries)
+	assert.Nil(t, got.Debug)
+	assert.Nil(t, got.Hosts)
+	assert.Nil(t, got.Name)
+	assert.Nil(t, got.TLS)
+	assert.Nil(t, got.Serve)
+	assert.Equal(t, time.Second, *got.Timeout)
+	// 默认值也算设置过
+	assert.Equal(t, "info", got.Log.Level)
+
+	got = testPointer{}
+	args := []string{"--retries", "0", "-d", "--host", "a", "--host", "b", "--cert", "c.pem", "n1"}
+	assert.NoError(t, New(args).SetExit(false).Bind(&got))
+	assert.Equal(t, 0, *got.Retries)
+	assert.True(t, *got.Debug)
+	assert.Equal(t, []string{"a", "b"}, *got.Hosts)
+	assert.Equal(t, "n1", *got.Name)
+	assert.Equal(t, &testPointerTLS{Cert: "c.pem"}, got.TLS)
+	assert.Nil(t, got.Serve)
+
+	// 已经分配过的指针直接使用
+	retries := 3
+	got = testPointer{Retries: &retries}
+	assert.NoError(t, New([]string{"-r", "5"}).SetExit(false).Bind(&got))
+	assert.Equal(t, 5, retries)
+}
+
+func Test_Pointer_EnvAndSubcommand(t *testing.T) {
+	os.Setenv("CLOP_POINTER_DEBUG", "false")
+	defer os.Unsetenv("CLOP_POINTER_DEBUG")
+
+	got := testPointer{}
+	assert.NoError(t, New([]string{"serve"}).SetExit(false).Bind(&got))
+	assert.False(t, *got.Debug)
+	assert.NotNil(t, got.Serve)
+	assert.Nil(t, got.Serve.Port)
+
+	got = testPointer{}
+	assert.NoError(t, New([]string{"serve", "-p", "80"}).SetExit(false).Bind(&got))
+	assert.Equal(t, 80, *got.Serve.Port)
+}
@@ -0,0 +1,193 @@
+package clop
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// @file最多嵌套的层数
+const maxResponseDepth = 10
+
+// 参数来自哪个@file的哪一行
+type argOrigin struct {
+	file string
+	line int
+}
+
+func (o argOrigin) String() string {
+	return fmt.Sprintf("%s:%d", o.file, o.line)
+}
+
+// 设置是否展开命令行里面的@file参数, 默认是false
+// 打开之后@file会被替换成文件里面的参数, 一行可以写多个参数, 支持单引号, 双引号, 反斜杠转义和#注释
+// 文件里面也可以使用@file, 最多嵌套10层, --后面的参数不展开
+func (c *Clop) SetResponseFiles(enable bool) *Clop {
+	c.responseFiles = enable
+	return c
+}
+
+// 在bindStruct之前展开@file, 展开之后的参数按顺序编号, GetIndex的先后关系不变
+func (c *Clop) expandResponseFiles() error {
+	if !c.responseFiles {
+		return nil
+	}
+
+	args := make([]string, 0, len(c.args))
+	var origins []argOrigin
+	for i, arg := range c.args {
+		if arg == optTerminator {
+			args = append(args, c.args[i:]...)
+			break
+		}
+
+		if len(arg) < 2 || arg[0] != '@' {
+			args = append(args, arg)
+			continue
+		}
+
+		for len(origins) < len(args) {
+			origins = append(origins, argOrigin{})
+		}
+
+		var err error
+		if args, origins, err = readResponseFile(arg[1:], args, origins, 1); err != nil {
+			return &ParseError{Kind: KindInvalidArgument, Arg: arg, Index: c.argsOffset + i, Source: SourceArgv,
+				Err: err, msg: fmt.Sprintf("error: response file %v", err)}
+		}
+	}
+
+	c.args = args
+	if origins != nil {
+		c.argOrigins = origins
+	}
+	return nil
+}
+
+func readResponseFile(path string, args []string, origins []argOrigin, depth int) ([]string, []argOrigin, error) {
+	if depth > maxResponseDepth {
+		return nil, nil, fmt.Errorf("%s: nested too deeply (max %d)", path, maxResponseDepth)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := splitResponse(path, string(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, t := range tokens {
+		if len(t.arg) > 1 && t.arg[0] == '@' && !t.quoted {
+			if args, origins, err = readResponseFile(t.arg[1:], args, origins, depth+1); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		args = append(args, t.arg)
+		origins = append(origins, t.argOrigin)
+	}
+	return args, origins, nil
+}
+
+type responseToken struct {
+	argOrigin
+	arg    string
+	quoted bool
+}
+
+// 按照shell的规则切分参数
+func splitResponse(path string, data string) (tokens []responseToken, err error) {
+	var (
+		buf     []byte
+		inToken bool
+		quoted  bool
+		quote   byte
+		start   int
+	)
+
+	line := 1
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, responseToken{argOrigin: argOrigin{file: path, line: start}, arg: string(buf), quoted: quoted})
+		}
+		buf, inToken, quoted = buf[:0], false, false
+	}
+
+	begin := func() {
+		if !inToken {
+			inToken, start = true, line
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if b == '\n' {
+			line++
+		}
+
+		switch {
+		case quote == '\'':
+			if b == '\'' {
+				quote = 0
+				continue
+			}
+			buf = append(buf, b)
+		case quote == '"':
+			switch {
+			case b == '"':
+				quote = 0
+			case b == '\\' && i+1 < len(data) && (data[i+1] == '"' || data[i+1] == '\\' || data[i+1] == '$' || data[i+1] == '`'):
+				i++
+				buf = append(buf, data[i])
+			case b == '\\' && i+1 < len(data) && data[i+1] == '\n':
+				i++
+				line++
+			default:
+				buf = append(buf, b)
+			}
+		case b == '\'' || b == '"':
+			begin()
+			quote, quoted = b, true
+		case b == '\\':
+			if i+1 >= len(data) {
+				continue
+			}
+			i++
+			if data[i] == '\n' {
+				line++
+				continue
+			}
+			begin()
+			buf = append(buf, data[i])
+		case b == ' ' || b == '\t' || b == '\r' || b == '\n':
+			flush()
+		case b == '#' && !inToken:
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		default:
+			begin()
+			buf = append(buf, b)
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("%s: unterminated %c quote", argOrigin{file: path, line: start}, quote)
+	}
+
+	flush()
+	return tokens, nil
+}
+
+// 参数来自@file的时候, 报错信息里面带上文件名和行号
+func (c *Clop) originMsg(index int) string {
+	origins := c.getRoot().argOrigins
+	pos := c.argsOffset + index
+	if pos < 0 || pos >= len(origins) || origins[pos].file == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (from %s)", origins[pos])
+}
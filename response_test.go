@@ -0,0 +1,74 @@
+package clop
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testResponse struct {
+	Verbose bool     `clop:"-v;--verbose" usage:"verbose"`
+	Port    int      `clop:"-p;--port" usage:"port"`
+	Name    string   `clop:"-n;--name" usage:"name"`
+	Files   []string `clop:"args=files" usage:"files"`
+}
+
+func Test_Response(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	nested := writeTestConfig(t, dir, "nested.txt", "c.go\n")
+	path := writeTestConfig(t, dir, "args.txt", "# build args\n-p 8080 --name 'hello world'\n"+
+		"a.go \"b c.go\" d\\ e.go\n@"+nested+"\n'@literal'\n")
+
+	got := testResponse{}
+	c := New([]string{"-v", "@" + path, "x.go", "--", "@" + path}).SetResponseFiles(true).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, testResponse{Verbose: true, Port: 8080, Name: "hello world",
+		Files: []string{"a.go", "b c.go", "d e.go", "c.go", "@literal", "x.go", "@" + path}}, got)
+
+	// 展开之后的位置保持先后关系
+	assert.True(t, c.GetIndex("verbose") < c.GetIndex("port"))
+	assert.True(t, c.GetIndex("port") < c.GetIndex("name"))
+
+	// 默认不展开
+	got = testResponse{}
+	assert.NoError(t, New([]string{"@" + path}).SetExit(false).Bind(&got))
+	assert.Equal(t, []string{"@" + path}, got.Files)
+}
+
+// 报错信息带上文件名和行号
+func Test_Response_Error(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestConfig(t, dir, "args.txt", "-v\n--port abc\n")
+	err = New([]string{"@" + path}).SetResponseFiles(true).SetExit(false).SetOutput(ioutil.Discard).Bind(&testResponse{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Contains(t, err.Error(), path+":2")
+
+	path = writeTestConfig(t, dir, "quote.txt", "-v\n--name 'abc\n")
+	err = New([]string{"-v", "@" + path}).SetResponseFiles(true).SetExit(false).SetOutput(ioutil.Discard).Bind(&testResponse{})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.Contains(t, err.Error(), path+":2: unterminated ' quote")
+
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 1, pe.Index)
+
+	// 递归引用自己
+	path = filepath.Join(dir, "loop.txt")
+	writeTestConfig(t, dir, "loop.txt", "@"+path)
+	err = New([]string{"@" + path}).SetResponseFiles(true).SetExit(false).SetOutput(ioutil.Discard).Bind(&testResponse{})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "nested too deeply")
+
+	err = New([]string{"@" + filepath.Join(dir, "not-found.txt")}).SetResponseFiles(true).SetExit(false).SetOutput(ioutil.Discard).Bind(&testResponse{})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
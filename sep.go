@@ -0,0 +1,68 @@
+package clop
+
+import (
+	"reflect"
+	"strings"
+)
+
+// 从clop tag里面取出sep=的值, 设置默认值时使用
+func tagSep(clop string) string {
+	for _, opt := range strings.Split(clop, ";") {
+		opt = strings.TrimLeft(opt, optSpace)
+		if strings.HasPrefix(opt, optSepEqual) {
+			return opt[len(optSepEqual):]
+		}
+	}
+	return ""
+}
+
+// 切分slice和map类型的值, 不需要切分时ok为false
+// slice类型只按照sep=切分, map类型的环境变量, 配置和默认值没有sep=时按照逗号切分
+func splitValue(val string, sep string, source ValueSource, value reflect.Value) (parts []string, ok bool) {
+	if isValueType(value.Type()) {
+		return nil, false
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+	case reflect.Map:
+		if len(val) > 0 && isDefvalJSON(StringToBytes(val)) {
+			return nil, false
+		}
+
+		if sep == "" && source != SourceArgv {
+			sep = ","
+		}
+	default:
+		return nil, false
+	}
+
+	if sep == "" {
+		return nil, false
+	}
+	return splitSep(val, sep), true
+}
+
+// 按照分隔符切分, 反斜杠加分隔符表示分隔符本身, 比如a\,b,c切分成a,b和c
+func splitSep(s string, sep string) (rv []string) {
+	if s == "" {
+		return nil
+	}
+
+	var b strings.Builder
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, `\`+sep):
+			b.WriteString(sep)
+			s = s[len(sep)+1:]
+		case strings.HasPrefix(s, sep):
+			rv = append(rv, b.String())
+			b.Reset()
+			s = s[len(sep):]
+		default:
+			b.WriteByte(s[0])
+			s = s[1:]
+		}
+	}
+	return append(rv, b.String())
+}
@@ -0,0 +1,56 @@
+package clop
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSep struct {
+	Tags  []string        `clop:"-t;--tags;sep=,;env=CLOP_SEP_TAGS" usage:"tags"`
+	Ports []int           `clop:"-p;--ports;sep=:" usage:"ports" default:"80:443"`
+	Waits []time.Duration `clop:"--waits;sep=," usage:"waits"`
+	Names []string        `clop:"--names" usage:"names"`
+}
+
+func Test_Sep(t *testing.T) {
+	got := testSep{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, testSep{Ports: []int{80, 443}}, got)
+
+	got = testSep{}
+	args := []string{"--tags", `a,b\,c`, "-t", "d", "-p", "1:2", "--waits", "1s,2ms", "--names", "x,y"}
+	assert.NoError(t, New(args).SetExit(false).Bind(&got))
+	assert.Equal(t, testSep{
+		Tags:  []string{"a", "b,c", "d"},
+		Ports: []int{1, 2},
+		Waits: []time.Duration{time.Second, 2 * time.Millisecond},
+		Names: []string{"x,y"},
+	}, got)
+}
+
+func Test_Sep_Env(t *testing.T) {
+	os.Setenv("CLOP_SEP_TAGS", "a,b")
+	defer os.Unsetenv("CLOP_SEP_TAGS")
+
+	got := testSep{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, []string{"a", "b"}, got.Tags)
+}
+
+func Test_Sep_Error(t *testing.T) {
+	err := New([]string{"-p", "1:x"}).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&testSep{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Contains(t, err.Error(), "Invalid value 'x' for '--ports'")
+}
+
+func Test_SplitSep(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, splitSep("a,b", ","))
+	assert.Equal(t, []string{"a,b", ""}, splitSep(`a\,b,`, ","))
+	assert.Equal(t, []string{`a\b`}, splitSep(`a\b`, ","))
+	assert.Equal(t, []string{"a", "b"}, splitSep("a::b", "::"))
+	assert.Nil(t, splitSep("", ","))
+}
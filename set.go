@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -90,11 +91,8 @@ func setFloatField(val string, bitSize int, field reflect.Value) error {
 	return err
 }
 
+// time.Time等标准库类型在setBuiltin里面处理, 其它结构体使用json
 func setStructField(val string, bitSize int, value reflect.Value) error {
-	switch value.Interface().(type) {
-	case time.Time:
-		//return setTimeField(val, bitSize, value)
-	}
 	return json.Unmarshal([]byte(val), value.Addr().Interface())
 }
 
@@ -121,8 +119,33 @@ func setSlice(val string, bitSize int, value reflect.Value) error {
 	return nil
 }
 
+// map类型, 每次设置一个key=value, json格式的值整个解析
 func setMapField(val string, bitSize int, value reflect.Value) error {
-	return json.Unmarshal([]byte(val), value.Addr().Interface())
+	if len(val) > 0 && isDefvalJSON(StringToBytes(val)) {
+		return json.Unmarshal([]byte(val), value.Addr().Interface())
+	}
+
+	pos := strings.IndexByte(val, '=')
+	if pos == -1 {
+		return fmt.Errorf("expected key=value")
+	}
+
+	typ := value.Type()
+	k := reflect.New(typ.Key()).Elem()
+	if err := setBase(val[:pos], k); err != nil {
+		return fmt.Errorf("invalid key '%s': %w", val[:pos], err)
+	}
+
+	v := reflect.New(typ.Elem()).Elem()
+	if err := setBase(val[pos+1:], v); err != nil {
+		return fmt.Errorf("invalid value '%s': %w", val[pos+1:], err)
+	}
+
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(typ))
+	}
+	value.SetMapIndex(k, v)
+	return nil
 }
 
 func setTimeDuration(val string, bitSize int, value reflect.Value) error {
@@ -139,6 +162,15 @@ func setTimeDuration(val string, bitSize int, value reflect.Value) error {
 }
 
 func setBase(val string, value reflect.Value) error {
+	// 标准库类型和自定义类型优先
+	if ok, err := setBuiltin(val, "", value); ok {
+		return err
+	}
+
+	if ok, err := setValue(val, value); ok {
+		return err
+	}
+
 	if value.Kind() == reflect.String {
 		value.SetString(val)
 		return nil
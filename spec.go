@@ -0,0 +1,198 @@
+package clop
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SpecVersion Spec()输出的文档格式版本, 格式有不兼容的修改时才会增加
+const SpecVersion = 1
+
+// --help=json输出Spec()
+const helpJSON = "help=json"
+
+// Spec 机器可读的命令行描述, 由(*Clop).Spec()生成
+// 和clop parse --spec使用的描述文件格式兼容
+type Spec struct {
+	Version     int           `json:"version" yaml:"version"` //文档格式版本, 等于SpecVersion
+	Name        string        `json:"name" yaml:"name"`
+	About       string        `json:"about,omitempty" yaml:"about,omitempty"`
+	AppVersion  string        `json:"app_version,omitempty" yaml:"app_version,omitempty"` //SetVersion设置的版本信息
+	Options     []SpecOption  `json:"options,omitempty" yaml:"options,omitempty"`
+	Args        []SpecOption  `json:"args,omitempty" yaml:"args,omitempty"`
+	Subcommands []SpecCommand `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+}
+
+// SpecCommand 命令或者子命令
+type SpecCommand struct {
+	Name        string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Aliases     []string      `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Usage       string        `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Options     []SpecOption  `json:"options,omitempty" yaml:"options,omitempty"`
+	Args        []SpecOption  `json:"args,omitempty" yaml:"args,omitempty"` //按照位置排列
+	Subcommands []SpecCommand `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+}
+
+// SpecOption 选项或者args参数
+type SpecOption struct {
+	Name      string   `json:"name,omitempty" yaml:"name,omitempty"` //args名
+	Short     string   `json:"short,omitempty" yaml:"short,omitempty"`
+	Long      string   `json:"long,omitempty" yaml:"long,omitempty"`
+	Aliases   []string `json:"aliases,omitempty" yaml:"aliases,omitempty"` //其它的选项名, 带-或者--
+	Env       string   `json:"env,omitempty" yaml:"env,omitempty"`
+	Type      string   `json:"type,omitempty" yaml:"type,omitempty"` //Go类型名, 比如int, []string, time.Duration
+	Usage     string   `json:"usage,omitempty" yaml:"usage,omitempty"`
+	Default   string   `json:"default,omitempty" yaml:"default,omitempty"`
+	Valid     string   `json:"valid,omitempty" yaml:"valid,omitempty"` //valid tag
+	Group     string   `json:"group,omitempty" yaml:"group,omitempty"` //group tag, 比如source;exclusive;required
+	Requires  []string `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Conflicts []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+	Implies   []string `json:"implies,omitempty" yaml:"implies,omitempty"` //name=value
+	Tag       string   `json:"tag,omitempty" yaml:"tag,omitempty"`         //影响解析方式的clop tag选项, 比如greedy;once
+}
+
+// Spec 返回描述整个命令树的json, 包括选项, 类型, 默认值, 环境变量, args, 子命令, 约束和版本信息
+// 需要在Register或者Bind之后调用
+func (c *Clop) Spec() ([]byte, error) {
+	return json.MarshalIndent(c.genSpec(), "", "  ")
+}
+
+func (c *Clop) genSpec() *Spec {
+	cmd := c.specCommand(nil, "")
+	return &Spec{
+		Version:     SpecVersion,
+		Name:        c.procName,
+		About:       c.about,
+		AppVersion:  c.getRoot().version,
+		Options:     cmd.Options,
+		Args:        cmd.Args,
+		Subcommands: cmd.Subcommands,
+	}
+}
+
+func (c *Clop) specCommand(names []string, usage string) SpecCommand {
+	cmd := SpecCommand{Usage: usage}
+	if len(names) > 0 {
+		cmd.Name, cmd.Aliases = names[0], names[1:]
+	}
+
+	groups := make(map[*Option]string)
+	for _, g := range c.groups {
+		tag := []string{g.name}
+		for _, v := range []struct {
+			set  bool
+			name string
+		}{{g.exclusive, groupExclusive}, {g.required, groupRequired}, {g.together, groupTogether}} {
+			if v.set {
+				tag = append(tag, v.name)
+			}
+		}
+
+		for _, o := range g.options {
+			groups[o] = strings.Join(tag, ";")
+		}
+	}
+
+	for _, o := range c.sortedOptions() {
+		if o.argsName == "" {
+			cmd.Options = append(cmd.Options, o.specOption(groups[o]))
+		}
+	}
+
+	// args按照注册的顺序, 也就是位置排列
+	for _, o := range c.envAndArgs {
+		if o.argsName != "" {
+			cmd.Args = append(cmd.Args, o.specOption(groups[o]))
+		}
+	}
+
+	used := make(map[*Subcommand]struct{}, len(c.subcommand))
+	subs := make([]*Subcommand, 0, len(c.subcommand))
+	for _, sub := range c.subcommand {
+		if _, ok := used[sub]; ok {
+			continue
+		}
+		used[sub] = struct{}{}
+		subs = append(subs, sub)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].names[0] < subs[j].names[0]
+	})
+
+	for _, sub := range subs {
+		cmd.Subcommands = append(cmd.Subcommands, sub.Clop.specCommand(sub.names, sub.usage))
+	}
+	return cmd
+}
+
+func (o *Option) specOption(group string) SpecOption {
+	s := SpecOption{
+		Name:      o.argsName,
+		Env:       o.envName,
+		Type:      o.pointer.Type().String(),
+		Usage:     o.usage,
+		Default:   o.showDefValue,
+		Valid:     o.valid,
+		Group:     group,
+		Requires:  o.requires,
+		Conflicts: o.conflicts,
+		Tag:       o.specTag(),
+	}
+
+	for i, name := range o.showShort {
+		if i == 0 {
+			s.Short = name
+			continue
+		}
+		s.Aliases = append(s.Aliases, "-"+name)
+	}
+
+	for i, name := range o.showLong {
+		if i == 0 {
+			s.Long = name
+			continue
+		}
+		s.Aliases = append(s.Aliases, "--"+name)
+	}
+
+	for _, v := range o.implies {
+		s.Implies = append(s.Implies, v.name+"="+v.value)
+	}
+	return s
+}
+
+// 影响解析方式的clop tag选项
+func (o *Option) specTag() string {
+	var tags []string
+	for _, v := range []struct {
+		set bool
+		tag string
+	}{
+		{o.greedy, optGreedy},
+		{o.once, optOnce},
+		{o.count, optCount},
+		{o.countMax > 0, optMaxEqual + strconv.Itoa(o.countMax)},
+		{o.negatable, optNegatable},
+		{o.sep != "", optSepEqual + o.sep},
+		{o.raw, optRaw},
+	} {
+		if v.set {
+			tags = append(tags, v.tag)
+		}
+	}
+	return strings.Join(tags, ";")
+}
+
+// 输出--help=json
+func (c *Clop) printSpec() error {
+	data, err := c.Spec()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
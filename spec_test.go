@@ -0,0 +1,96 @@
+package clop
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpecPush struct {
+	Force bool   `clop:"-f;--force" usage:"force"`
+	Image string `clop:"args=image" usage:"image name" valid:"required"`
+}
+
+type testSpec struct {
+	Port    int           `clop:"-p;--port;env=CLOP_SPEC_PORT" usage:"port" default:"80"`
+	Verbose int           `clop:"-v;--verbose;count;max=3" usage:"verbose"`
+	Color   bool          `clop:"--color;negatable;implies=verbose" usage:"color"`
+	File    string        `clop:"--file;conflicts=url" group:"source;exclusive" usage:"file"`
+	URL     string        `clop:"--url" group:"source" requires:"port" usage:"url"`
+	Timeout time.Duration `clop:"--timeout" usage:"timeout"`
+	Push    testSpecPush  `clop:"subcommand=push,p" usage:"push image"`
+}
+
+func Test_Spec(t *testing.T) {
+	c := New(nil).SetProcName("deploy").SetAbout("deploy the app").SetVersion("v1.0")
+	assert.NoError(t, c.Register(&testSpec{}))
+
+	data, err := c.Spec()
+	assert.NoError(t, err)
+
+	got := Spec{}
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, Spec{
+		Version:    SpecVersion,
+		Name:       "deploy",
+		About:      "deploy the app",
+		AppVersion: "v1.0",
+		Options: []SpecOption{
+			{Long: "color", Type: "bool", Usage: "color", Implies: []string{"verbose=true"}, Tag: "negatable"},
+			{Long: "file", Type: "string", Usage: "file", Group: "source;exclusive", Conflicts: []string{"url"}},
+			{Short: "p", Long: "port", Env: "CLOP_SPEC_PORT", Type: "int", Usage: "port", Default: "80"},
+			{Long: "timeout", Type: "time.Duration", Usage: "timeout"},
+			{Long: "url", Type: "string", Usage: "url", Group: "source;exclusive", Requires: []string{"port"}},
+			{Short: "v", Long: "verbose", Type: "int", Usage: "verbose", Tag: "count;max=3"},
+		},
+		Subcommands: []SpecCommand{{
+			Name:    "push",
+			Aliases: []string{"p"},
+			Usage:   "push image",
+			Options: []SpecOption{{Short: "f", Long: "force", Type: "bool", Usage: "force"}},
+			Args:    []SpecOption{{Name: "image", Type: "string", Usage: "image name", Valid: "required"}},
+		}},
+	}, got)
+}
+
+// AddOption注册的选项也在Spec里面
+func Test_Spec_Builder(t *testing.T) {
+	var names []string
+	c := New(nil).SetProcName("app")
+	assert.NoError(t, c.AddOption(OptionSpec{Long: "name", Tag: "greedy", Valid: "required", Requires: []string{"x"}, Target: &names}))
+
+	data, err := c.Spec()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"tag": "greedy"`)
+	assert.Contains(t, string(data), `"valid": "required"`)
+	assert.Contains(t, string(data), `"type": "[]string"`)
+}
+
+// 隐藏的--help=json
+func Test_Spec_HelpJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := New([]string{"--help=json"}).SetProcName("deploy").SetExit(false).SetOutput(&out).Bind(&testSpec{})
+	assert.ErrorIs(t, err, ErrHelp)
+
+	got := Spec{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "deploy", got.Name)
+	assert.Len(t, got.Subcommands, 1)
+
+	// 子命令输出子命令的描述
+	out.Reset()
+	err = New([]string{"push", "--help=json"}).SetProcName("deploy").SetExit(false).SetOutput(&out).Bind(&testSpec{})
+	assert.ErrorIs(t, err, ErrHelp)
+	got = Spec{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "push", got.Name)
+	assert.Len(t, got.Args, 1)
+
+	// 不在帮助信息里面显示
+	out.Reset()
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testSpec{}), ErrHelp)
+	assert.NotContains(t, out.String(), "json")
+}
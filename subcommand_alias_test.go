@@ -0,0 +1,56 @@
+package clop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type aliasRemove struct {
+	Force bool     `clop:"-f;--force" usage:"force"`
+	Files []string `clop:"args=files" usage:"files"`
+}
+
+type aliasList struct {
+	All bool `clop:"-a;--all" usage:"all"`
+}
+
+type aliasTool struct {
+	Remove aliasRemove `clop:"subcommand=remove,rm" usage:"remove files"`
+	List   aliasList   `clop:"subcommand=list, ls" usage:"list files"`
+}
+
+func Test_Subcommand_Alias(t *testing.T) {
+	for _, name := range []string{"remove", "rm"} {
+		got := aliasTool{}
+		c := New([]string{name, "-f", "a.txt"}).SetExit(false)
+		assert.NoError(t, c.Bind(&got))
+		assert.Equal(t, aliasRemove{Force: true, Files: []string{"a.txt"}}, got.Remove)
+		assert.True(t, c.IsSetSubcommand("remove"))
+		assert.True(t, c.IsSetSubcommand("rm"))
+		assert.False(t, c.IsSetSubcommand("ls"))
+	}
+
+	got := aliasTool{}
+	c := New([]string{"ls", "-a"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.True(t, got.List.All)
+	assert.True(t, c.IsSetSubcommand("list"))
+}
+
+func Test_Subcommand_AliasHelp(t *testing.T) {
+	var out bytes.Buffer
+	c := New([]string{"-h"}).SetProcName("tool").SetExit(false).SetOutput(&out)
+	assert.ErrorIs(t, c.Bind(&aliasTool{}), ErrHelp)
+	assert.Contains(t, out.String(), "list, ls")
+	assert.Contains(t, out.String(), "remove, rm")
+	assert.NotContains(t, out.String(), "\n    rm")
+
+	out.Reset()
+	c = New(nil).SetProcName("tool")
+	assert.NoError(t, c.Register(&aliasTool{}))
+	assert.NoError(t, c.GenCompletion("bash", &out))
+	assert.Contains(t, out.String(), `"tool:rm") cmdpath="tool remove" ;;`)
+	assert.Contains(t, out.String(), `"tool:remove") cmdpath="tool remove" ;;`)
+}
@@ -0,0 +1,83 @@
+package clop
+
+import "strings"
+
+// 从root到当前子命令的完整命令名, 比如tool cluster node add
+func (c *Clop) fullName() string {
+	var names []string
+	for p := c; p != nil; p = p.parent {
+		if p.procName != "" {
+			names = append(names, p.procName)
+		}
+	}
+
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, " ")
+}
+
+// SelectedPath 命令行选中的子命令路径, 使用子命令的主名字
+// 比如tool cluster node add -f, 返回[]string{"cluster", "node", "add"}
+func (c *Clop) SelectedPath() []string {
+	if selected := c.getRoot().selected; selected != nil {
+		return selected.subcommandPath()
+	}
+	return nil
+}
+
+// 从root到当前子命令的路径, 不包括root
+func (c *Clop) subcommandPath() (path []string) {
+	for p := c; p != nil && p.parent != nil; p = p.parent {
+		path = append(path, p.procName)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// GetSubcommand 根据路径查找子命令, 路径里面可以使用别名
+// 没有找到返回nil
+func (c *Clop) GetSubcommand(path ...string) *Clop {
+	p := c
+	for _, name := range path {
+		sub, ok := p.subcommand[name]
+		if !ok {
+			return nil
+		}
+		p = sub.Clop
+	}
+	return p
+}
+
+// IsSetSubcommandPath 判断路径上的子命令是否被选中, 路径里面可以使用别名
+// 比如tool cluster node add, IsSetSubcommandPath("cluster", "node")也返回true
+func (c *Clop) IsSetSubcommandPath(path ...string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	sub := c.GetSubcommand(path...)
+	if sub == nil {
+		return false
+	}
+
+	for p := c.getRoot().selected; p != nil; p = p.parent {
+		if p == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedPath 命令行选中的子命令路径
+func SelectedPath() []string {
+	return CommandLine.SelectedPath()
+}
+
+// IsSetSubcommandPath 判断路径上的子命令是否被选中
+func IsSetSubcommandPath(path ...string) bool {
+	return CommandLine.IsSetSubcommandPath(path...)
+}
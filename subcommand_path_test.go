@@ -0,0 +1,81 @@
+package clop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pathNodeAdd struct {
+	Force bool   `clop:"-f;--force" usage:"force"`
+	Name  string `clop:"args=name" usage:"node name" valid:"required"`
+}
+
+type pathNode struct {
+	Add pathNodeAdd `clop:"subcommand=add" usage:"add node"`
+}
+
+type pathCluster struct {
+	Node pathNode `clop:"subcommand=node,no" usage:"manage nodes"`
+}
+
+type pathUserAdd struct {
+	Admin bool `clop:"-a;--admin" usage:"admin user"`
+}
+
+type pathUser struct {
+	Add pathUserAdd `clop:"subcommand=add" usage:"add user"`
+}
+
+type pathTool struct {
+	Cluster pathCluster `clop:"subcommand=cluster" usage:"manage clusters"`
+	User    pathUser    `clop:"subcommand=user" usage:"manage users"`
+}
+
+func Test_Subcommand_Path(t *testing.T) {
+	got := pathTool{}
+	c := New([]string{"cluster", "no", "add", "-f", "n1"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.Equal(t, pathNodeAdd{Force: true, Name: "n1"}, got.Cluster.Node.Add)
+	assert.Equal(t, []string{"cluster", "node", "add"}, c.SelectedPath())
+	assert.True(t, c.IsSetSubcommandPath("cluster", "node", "add"))
+	assert.True(t, c.IsSetSubcommandPath("cluster", "no", "add"))
+	assert.True(t, c.IsSetSubcommandPath("cluster"))
+	assert.False(t, c.IsSetSubcommandPath("user", "add"))
+	assert.False(t, c.IsSetSubcommandPath("cluster", "unknown"))
+
+	add := c.GetSubcommand("cluster", "node", "add")
+	assert.Less(t, add.GetIndex("force"), add.GetIndex("name"))
+	_, ok := c.GetSubcommand("user", "add").GetIndexEx("admin")
+	assert.True(t, ok)
+	assert.Nil(t, c.GetSubcommand("cluster", "add"))
+
+	got = pathTool{}
+	c = New([]string{"user", "add", "-a"}).SetExit(false)
+	assert.NoError(t, c.Bind(&got))
+	assert.True(t, got.User.Add.Admin)
+	assert.Equal(t, []string{"user", "add"}, c.SelectedPath())
+	assert.False(t, c.IsSetSubcommandPath("cluster", "node", "add"))
+
+	c = New([]string{"user"}).SetExit(false)
+	assert.NoError(t, c.Bind(&pathTool{}))
+	assert.Equal(t, []string{"user"}, c.SelectedPath())
+	assert.False(t, c.IsSetSubcommandPath("user", "add"))
+}
+
+// 数据校验使用最后一层子命令的结构体
+func Test_Subcommand_PathValid(t *testing.T) {
+	c := New([]string{"cluster", "node", "add"}).SetExit(false).SetOutput(&bytes.Buffer{})
+	assert.ErrorIs(t, c.Bind(&pathTool{}), ErrMissingRequired)
+
+	c = New([]string{"user", "add"}).SetExit(false)
+	assert.NoError(t, c.Bind(&pathTool{}))
+}
+
+func Test_Subcommand_PathHelp(t *testing.T) {
+	var out bytes.Buffer
+	c := New([]string{"cluster", "node", "add", "-h"}).SetProcName("tool").SetExit(false).SetOutput(&out)
+	assert.ErrorIs(t, c.Bind(&pathTool{}), ErrHelp)
+	assert.Contains(t, out.String(), "tool cluster node add [Flags] [Options] <name>")
+}
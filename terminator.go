@@ -0,0 +1,31 @@
+package clop
+
+// 命令行里面的--, 后面的参数都不再当作选项解析
+const optTerminator = "--"
+
+// 设置选项和参数是否可以混在一起, 默认是true
+// 设置为false时, 遇到第一个参数(不是子命令)就停止解析选项, 后面的参数原样保存, 和POSIXLY_CORRECT的行为一样
+func (c *Clop) SetInterspersed(interspersed bool) *Clop {
+	c.interspersed = interspersed
+	return c
+}
+
+// 停止解析选项, c.args[from:]原样保存到rawArgs
+func (c *Clop) stopParsing(index *int, from int) {
+	c.rawArgs = c.args[from:]
+	c.rawOffset = from
+	*index = len(c.args)
+}
+
+// 没有raw选项接收的时候, rawArgs和普通的参数一样处理
+func (c *Clop) addRawArgs() {
+	for _, o := range c.envAndArgs {
+		if o.raw {
+			return
+		}
+	}
+
+	for i, arg := range c.rawArgs {
+		c.unparsedArgs = append(c.unparsedArgs, unparsedArg{arg: arg, index: c.rawOffset + i})
+	}
+}
@@ -0,0 +1,67 @@
+package clop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTerminator struct {
+	Verbose bool     `clop:"-v;--verbose" usage:"verbose"`
+	Name    string   `clop:"-n;--name" usage:"name"`
+	Files   []string `clop:"args=files" usage:"files"`
+}
+
+// --后面的参数都当作普通参数
+func Test_Terminator(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		need testTerminator
+	}{
+		{[]string{"--"}, testTerminator{}},
+		{[]string{"-v", "--", "-n", "x"}, testTerminator{Verbose: true, Files: []string{"-n", "x"}}},
+		{[]string{"a", "--", "--verbose", "--", "b"}, testTerminator{Files: []string{"a", "--verbose", "--", "b"}}},
+		{[]string{"-n", "x", "a", "-v"}, testTerminator{Verbose: true, Name: "x", Files: []string{"a"}}},
+	} {
+		got := testTerminator{}
+		assert.NoError(t, New(tc.args).SetExit(false).Bind(&got), tc.args)
+		assert.Equal(t, tc.need, got, tc.args)
+	}
+}
+
+type testTerminatorRaw struct {
+	Debug bool     `clop:"-d;--debug" usage:"debug"`
+	Tags  []string `clop:"-t;--tag;greedy" usage:"tags"`
+	Cmd   string   `clop:"args=cmd" usage:"command"`
+	Rest  []string `clop:"args=rest;raw" usage:"command args"`
+}
+
+// raw参数原样接收--后面的参数, 不会被其它args消费
+func Test_Terminator_Raw(t *testing.T) {
+	got := testTerminatorRaw{}
+	assert.NoError(t, New([]string{"-d", "ls", "--", "-la", "--color=auto"}).SetExit(false).Bind(&got))
+	assert.Equal(t, testTerminatorRaw{Debug: true, Cmd: "ls", Rest: []string{"-la", "--color=auto"}}, got)
+
+	// greedy选项遇到--停止
+	got = testTerminatorRaw{}
+	assert.NoError(t, New([]string{"-t", "a", "b", "--", "c"}).SetExit(false).Bind(&got))
+	assert.Equal(t, testTerminatorRaw{Tags: []string{"a", "b"}, Rest: []string{"c"}}, got)
+}
+
+func Test_Terminator_RawType(t *testing.T) {
+	type raw struct {
+		Rest string `clop:"args=rest;raw"`
+	}
+	assert.ErrorIs(t, New(nil).SetExit(false).Bind(&raw{}), ErrUnsupportedType)
+}
+
+// SetInterspersed(false), 第一个参数后面的都不是选项
+func Test_Terminator_Interspersed(t *testing.T) {
+	got := testTerminatorRaw{}
+	assert.NoError(t, New([]string{"-d", "ls", "-la", "--", "x"}).SetInterspersed(false).SetExit(false).Bind(&got))
+	assert.Equal(t, testTerminatorRaw{Debug: true, Cmd: "ls", Rest: []string{"-la", "--", "x"}}, got)
+
+	got2 := testTerminator{}
+	assert.NoError(t, New([]string{"-v", "a", "-n", "x"}).SetInterspersed(false).SetExit(false).Bind(&got2))
+	assert.Equal(t, testTerminator{Verbose: true, Files: []string{"a", "-n", "x"}}, got2)
+}
@@ -0,0 +1,88 @@
+package clop
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// Value 自定义类型实现该接口, 就可以直接作为选项的类型
+// 指针接收者和值接收者都可以
+type Value interface {
+	Set(string) error
+	String() string
+}
+
+// 可选接口, 返回的类型名显示在帮助信息里面, 比如--level <level>
+type valueTyper interface {
+	Type() string
+}
+
+var (
+	typeValue           = reflect.TypeOf((*Value)(nil)).Elem()
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// 是否是内置的标准库类型, 或者实现了Value, encoding.TextUnmarshaler接口
+func isValueType(typ reflect.Type) bool {
+	if _, ok := builtinTypes[typ]; ok {
+		return true
+	}
+
+	if typ.Kind() != reflect.Ptr {
+		typ = reflect.PtrTo(typ)
+	}
+	return typ.Implements(typeValue) || typ.Implements(typeTextUnmarshaler)
+}
+
+// 取出实现Value或者encoding.TextUnmarshaler接口的变量
+func valueInterface(value reflect.Value) interface{} {
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		value = value.Addr()
+	}
+
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+
+	if !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// 使用Value或者encoding.TextUnmarshaler接口设置值, 没有实现接口时ok为false
+func setValue(val string, value reflect.Value) (ok bool, err error) {
+	switch v := valueInterface(value).(type) {
+	case Value:
+		return true, v.Set(val)
+	case encoding.TextUnmarshaler:
+		return true, v.UnmarshalText([]byte(val))
+	}
+	return false, nil
+}
+
+// 帮助信息里面显示的类型名
+func valueTypeName(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+
+	typ := value.Type()
+	if b, ok := builtinTypes[typ]; ok {
+		return b.name
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice:
+		if b, ok := builtinTypes[typ.Elem()]; ok {
+			return b.name
+		}
+	case reflect.Map:
+		return "key=value"
+	}
+
+	if t, ok := valueInterface(value).(valueTyper); ok {
+		return t.Type()
+	}
+	return ""
+}
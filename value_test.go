@@ -0,0 +1,102 @@
+package clop
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLevel int
+
+func (l *testLevel) Set(s string) error {
+	switch s {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %s", s)
+	}
+	return nil
+}
+
+func (l testLevel) String() string {
+	return [...]string{"debug", "info", "error"}[l]
+}
+
+func (l testLevel) Type() string {
+	return "level"
+}
+
+type testHostPort struct {
+	Host string
+	Port int
+}
+
+func (h *testHostPort) Set(s string) (err error) {
+	pos := strings.LastIndexByte(s, ':')
+	if pos == -1 {
+		return fmt.Errorf("missing port in %s", s)
+	}
+
+	h.Host = s[:pos]
+	h.Port, err = strconv.Atoi(s[pos+1:])
+	return err
+}
+
+func (h *testHostPort) String() string {
+	return fmt.Sprintf("%s:%d", h.Host, h.Port)
+}
+
+type testSemver struct {
+	Major, Minor, Patch int
+}
+
+func (v *testSemver) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "v%d.%d.%d", &v.Major, &v.Minor, &v.Patch)
+	return err
+}
+
+type testValue struct {
+	Level   testLevel      `clop:"-l;--level" usage:"log level" default:"info"`
+	Addr    testHostPort   `clop:"-a;--addr" usage:"listen address" default:"127.0.0.1:80"`
+	Peers   []testHostPort `clop:"-p;--peer" usage:"peers"`
+	Version testSemver     `clop:"--version" usage:"version"`
+	Levels  []testLevel    `clop:"--levels" usage:"levels"`
+}
+
+func Test_Value(t *testing.T) {
+	got := testValue{}
+	assert.NoError(t, New(nil).SetExit(false).Bind(&got))
+	assert.Equal(t, testValue{Level: 1, Addr: testHostPort{"127.0.0.1", 80}}, got)
+
+	got = testValue{}
+	args := []string{"-l", "error", "--addr", "0.0.0.0:8080", "-p", "a:1", "-p", "b:2", "--version", "v1.2.3", "--levels", "debug", "--levels", "info"}
+	assert.NoError(t, New(args).SetExit(false).Bind(&got))
+	assert.Equal(t, testValue{
+		Level:   2,
+		Addr:    testHostPort{"0.0.0.0", 8080},
+		Peers:   []testHostPort{{"a", 1}, {"b", 2}},
+		Version: testSemver{1, 2, 3},
+		Levels:  []testLevel{0, 1},
+	}, got)
+}
+
+func Test_Value_Error(t *testing.T) {
+	err := New([]string{"-l", "trace"}).SetExit(false).SetOutput(&bytes.Buffer{}).Bind(&testValue{})
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Equal(t, "error: Invalid value 'trace' for '--level': unknown level trace", err.Error())
+}
+
+func Test_Value_Help(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, New([]string{"-h"}).SetExit(false).SetOutput(&out).Bind(&testValue{}), ErrHelp)
+	assert.Contains(t, out.String(), "-l,--level <level>")
+	assert.Contains(t, out.String(), "-a,--addr ")
+}